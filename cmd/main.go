@@ -74,11 +74,21 @@ func main() {
 	// Init data layer
 	repo := repository.NewRepository(db)
 
+	// Init service
+	svc := service.NewService(repo, config.Token)
+
+	// Init signing key
+	err = svc.EnsureSigningKey(ctx)
+	if err != nil {
+		logrus.Error("Error initializing signing key", "error", err)
+		os.Exit(1)
+	}
+	go svc.RunKeyRotation(ctx)
+	go svc.RunGrantCleanup(ctx)
+
 	//oauth server
-	srv := service.GetOauthServer(repo)
+	srv := service.GetOauthServer(repo, svc, config.Token)
 
-	// Init service
-	svc := service.NewService(repo)
 	// Init handler
 	handler := http.NewHandler(config.HTTP, svc, token, srv)
 
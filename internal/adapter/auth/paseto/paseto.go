@@ -86,6 +86,8 @@ func (pt *PasetoToken) VerifyToken(token string) (*domain.TokenPayload, error) {
 	if err != nil {
 		return nil, domain.ErrInvalidToken
 	}
+	payload.IssuedAt, _ = parsedToken.GetIssuedAt()
+	payload.ExpiredAt, _ = parsedToken.GetExpiration()
 
 	return payload, nil
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -22,7 +23,16 @@ type (
 	}
 	// Token contains all the environment variables for the token service
 	Token struct {
-		Duration string
+		Duration            string
+		SigningAlgorithm    string
+		KeySecret           string
+		KeyActivationDelay  string
+		KeyRotationInterval string
+		KeyRetention        string
+		SessionLifetime     string
+		RegistrationScopes  string
+		Issuer              string
+		ClientCAFile        string
 	}
 	// Redis contains all the environment variables for the cache service
 	Redis struct {
@@ -40,10 +50,14 @@ type (
 	}
 	// HTTP contains all the environment variables for the http server
 	HTTP struct {
-		Env            string
-		URL            string
-		Port           string
-		AllowedOrigins string
+		Env             string
+		URL             string
+		Port            string
+		AllowedOrigins  string
+		Issuer          string
+		TLSCertFile     string
+		TLSKeyFile      string
+		TLSClientCAFile string
 	}
 )
 
@@ -62,7 +76,17 @@ func New() (*Container, error) {
 	}
 
 	token := &Token{
-		Duration: os.Getenv("TOKEN_DURATION"),
+		Duration:            os.Getenv("TOKEN_DURATION"),
+		SigningAlgorithm:    os.Getenv("TOKEN_SIGNING_ALGORITHM"),
+		KeySecret:           os.Getenv("TOKEN_KEY_SECRET"),
+		KeyActivationDelay:  os.Getenv("TOKEN_KEY_ACTIVATION_DELAY"),
+		KeyRotationInterval: os.Getenv("TOKEN_KEY_ROTATION_INTERVAL"),
+		KeyRetention:        os.Getenv("TOKEN_KEY_RETENTION"),
+		SessionLifetime:     os.Getenv("TOKEN_SESSION_LIFETIME"),
+		RegistrationScopes:  os.Getenv("TOKEN_REGISTRATION_SCOPES"),
+	}
+	if token.SigningAlgorithm == "" {
+		token.SigningAlgorithm = "RS256"
 	}
 
 	redis := &Redis{
@@ -80,11 +104,21 @@ func New() (*Container, error) {
 	}
 
 	http := &HTTP{
-		Env:            os.Getenv("APP_ENV"),
-		URL:            os.Getenv("HTTP_URL"),
-		Port:           os.Getenv("HTTP_PORT"),
-		AllowedOrigins: os.Getenv("HTTP_ALLOWED_ORIGINS"),
+		Env:             os.Getenv("APP_ENV"),
+		URL:             os.Getenv("HTTP_URL"),
+		Port:            os.Getenv("HTTP_PORT"),
+		AllowedOrigins:  os.Getenv("HTTP_ALLOWED_ORIGINS"),
+		Issuer:          strings.TrimSuffix(os.Getenv("HTTP_ISSUER"), "/"),
+		TLSCertFile:     os.Getenv("HTTP_TLS_CERT_FILE"),
+		TLSKeyFile:      os.Getenv("HTTP_TLS_KEY_FILE"),
+		TLSClientCAFile: os.Getenv("HTTP_TLS_CLIENT_CA_FILE"),
+	}
+	if http.Issuer == "" {
+		http.Issuer = "http://" + http.URL + ":" + http.Port + "/api/v1/auth"
 	}
+	// The issuer is compared as is by clients, so every token, document and uri uses it without a trailing slash
+	token.Issuer = http.Issuer
+	token.ClientCAFile = http.TLSClientCAFile
 
 	return &Container{
 		app,
@@ -1 +1,309 @@
 package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
+	"gopkg.in/oauth2.v3"
+	"gopkg.in/oauth2.v3/server"
+)
+
+// Authorize 		godoc
+// @Summary			Authorization endpoint
+// @Description		Sign the user in and redirect back to the client with an authorization code. PKCE (S256) is required for public clients.
+// @Tags			Connect
+// @Accept			x-www-form-urlencoded
+// @Produce			html
+// @Param			response_type			query		string		true		"Must be code"
+// @Param			client_id				query		string		true		"Client id"
+// @Param			redirect_uri			query		string		true		"Registered redirect uri"
+// @Param			scope					query		string		false		"Space separated scopes"
+// @Param			state					query		string		false		"Opaque client state"
+// @Param			nonce					query		string		false		"OpenID Connect nonce"
+// @Param			code_challenge			query		string		false		"PKCE code challenge"
+// @Param			code_challenge_method	query		string		false		"PKCE method, must be S256"
+// @Param			request_uri				query		string		false		"request_uri returned by /connect/par, replacing the other parameters"
+// @Success			302
+// @Router			/connect/authorize 		[get]
+func (h *Handler) Authorize(ctx *gin.Context) {
+	var req domain.AuthorizeRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if req.RequestURI != "" {
+		pushed, err := h.svc.GetPushedAuthorizationRequest(ctx, req.ClientID, req.RequestURI)
+		if err != nil {
+			ErrorResponse(ctx, http.StatusBadRequest, err)
+			return
+		}
+		pushed.Email, pushed.Password = req.Email, req.Password
+		pushed.Consent, pushed.ConsentScopes = req.Consent, req.ConsentScopes
+		req = *pushed
+	}
+	client, err := h.svc.GetAuthorizeClient(ctx, req.ClientID, req.RedirectURI)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	ar := &server.AuthorizeRequest{
+		ResponseType: oauth2.Code,
+		ClientID:     req.ClientID,
+		Scope:        req.Scope,
+		RedirectURI:  req.RedirectURI,
+		State:        req.State,
+		Request:      ctx.Request,
+	}
+	if err := h.svc.ValidateAuthorizeRequest(ctx, client, &req); err != nil {
+		h.authorizeRedirect(ctx, ar, h.authorizeErrorData(err))
+		return
+	}
+	session := h.userSession(ctx)
+	if session == nil {
+		if ctx.Request.Method != http.MethodPost || req.Email == "" {
+			h.loginPage(ctx, http.StatusOK, client, &req, "")
+			return
+		}
+		if !validCSRFToken(ctx) {
+			h.loginPage(ctx, http.StatusForbidden, client, &req, csrfError)
+			return
+		}
+		user, err := h.svc.LoginUser(ctx, &domain.LoginRequest{
+			Email:    req.Email,
+			Password: req.Password,
+		})
+		if err != nil {
+			h.loginPage(ctx, http.StatusUnauthorized, client, &req, loginError(err))
+			return
+		}
+		session, err = h.svc.CreateUserSession(ctx, strconv.FormatUint(uint64(user.ID), 10))
+		if err != nil {
+			h.authorizeRedirect(ctx, ar, h.authorizeErrorData(err))
+			return
+		}
+		setSessionCookie(ctx, session.SessionID)
+	}
+	ar.UserID = session.SubjectID
+	if client.RequireConsent() {
+		scope, ok := h.authorizeConsent(ctx, client, session, &req, ar)
+		if !ok {
+			return
+		}
+		req.Scope, ar.Scope = scope, scope
+	}
+	if err := h.svc.AddSessionClient(ctx, session.SessionID, req.ClientID); err != nil {
+		h.authorizeRedirect(ctx, ar, h.authorizeErrorData(err))
+		return
+	}
+	ti, err := h.srv.GetAuthorizeToken(ar)
+	if err != nil {
+		h.authorizeRedirect(ctx, ar, h.authorizeErrorData(err))
+		return
+	}
+	err = h.svc.SaveAuthorizationCode(ctx, ti.GetCode(), &domain.AuthorizationCode{
+		ClientID:            req.ClientID,
+		SubjectID:           ar.UserID,
+		RedirectURI:         req.RedirectURI,
+		Scope:               req.Scope,
+		Nonce:               req.Nonce,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+		AuthTime:            session.AuthTime,
+		Sid:                 session.Sid,
+	}, ti.GetCodeCreateAt().Add(ti.GetCodeExpiresIn()))
+	if err != nil {
+		h.authorizeRedirect(ctx, ar, h.authorizeErrorData(err))
+		return
+	}
+	if req.RequestURI != "" {
+		if err := h.svc.DeletePushedAuthorizationRequest(ctx, req.RequestURI); err != nil {
+			h.authorizeRedirect(ctx, ar, h.authorizeErrorData(err))
+			return
+		}
+	}
+	h.authorizeRedirect(ctx, ar, h.srv.GetAuthorizeData(ar.ResponseType, ti))
+}
+
+// Token 			godoc
+// @Summary			Token endpoint
+// @Description		Exchange a grant for an access token. Authorization codes issued with PKCE require code_verifier. An id_token is returned when the openid scope was granted.
+// @Tags			Connect
+// @Accept			x-www-form-urlencoded
+// @Produce			json
+// @Param			grant_type		formData	string		true		"Grant type"
+// @Param			client_id		formData	string		false		"Client id"
+// @Param			client_secret	formData	string		false		"Client secret, omitted by public clients"
+// @Param			code			formData	string		false		"Authorization code"
+// @Param			redirect_uri	formData	string		false		"Redirect uri used at the authorization endpoint"
+// @Param			code_verifier	formData	string		false		"PKCE code verifier"
+// @Param			client_assertion_type	formData	string	false	"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+// @Param			client_assertion		formData	string	false	"Client assertion signed with a registered key or signing secret"
+// @Param			subject_token	formData	string		false		"Access token to exchange"
+// @Param			actor_token		formData	string		false		"Access token of the party acting for the subject"
+// @Param			audience		formData	string		false		"Audience of the exchanged token"
+// @Param			resource		formData	string		false		"Resource indicator limiting the audience and scopes of the access token, may be repeated"
+// @Param			DPoP			header		string		false		"DPoP proof binding the issued tokens to its key"
+// @Success			200
+// @Router			/connect/token 	[post]
+func (h *Handler) Token(ctx *gin.Context) {
+	proof, err := h.tokenDPoPProof(ctx)
+	if err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	if proof != nil {
+		ctx.Request = ctx.Request.WithContext(domain.WithDPoPProof(ctx.Request.Context(), proof))
+	}
+	switch ctx.Request.FormValue("grant_type") {
+	case domain.GrantTypeDeviceCode:
+		h.deviceToken(ctx)
+		return
+	case domain.GrantTypeTokenExchange:
+		h.exchangeToken(ctx)
+		return
+	}
+	gt, tgr, err := h.srv.ValidationTokenRequest(ctx.Request)
+	if err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	var code *domain.AuthorizationCode
+	if gt == oauth2.AuthorizationCode {
+		code, err = h.svc.VerifyAuthorizationCode(ctx, tgr.Code, tgr.ClientID, ctx.Request.FormValue("code_verifier"))
+		if err != nil {
+			h.tokenError(ctx, err)
+			return
+		}
+	}
+	ti, err := h.srv.GetAccessToken(gt, tgr)
+	if err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	if code == nil {
+		code = &domain.AuthorizationCode{}
+	}
+	h.issueToken(ctx, ti, code)
+}
+
+// issueToken writes the token response, with an id_token when the openid scope was granted to a user.
+// The nonce, authentication time and session of the authorization are copied into the id_token.
+func (h *Handler) issueToken(ctx *gin.Context, ti oauth2.TokenInfo, code *domain.AuthorizationCode) {
+	data := h.tokenData(ctx, ti)
+	if domain.HasScope(ti.GetScope(), domain.ScopeOpenID) && ti.GetUserID() != "" {
+		idToken, err := h.svc.CreateIDToken(ctx, &domain.IDTokenRequest{
+			ClientID:    ti.GetClientID(),
+			SubjectID:   ti.GetUserID(),
+			Scope:       ti.GetScope(),
+			AccessToken: ti.GetAccess(),
+			Nonce:       code.Nonce,
+			AuthTime:    code.AuthTime,
+			Sid:         code.Sid,
+		})
+		if err != nil {
+			h.tokenError(ctx, err)
+			return
+		}
+		data["id_token"] = idToken
+	}
+	h.tokenResponse(ctx, http.StatusOK, data)
+}
+
+// csrfError is shown on a form posted without the csrf token of the browser
+const csrfError = "The form has expired, please try again."
+
+// loginError returns the message shown on a sign in form, without revealing internal errors
+func loginError(err error) string {
+	if err == domain.ErrLockedOut {
+		return err.Error()
+	}
+	return domain.ErrInvalidCredentials.Error()
+}
+
+// loginPage renders the sign in form for an authorization request
+func (h *Handler) loginPage(ctx *gin.Context, code int, client *domain.Client, req *domain.AuthorizeRequest, message string) {
+	name := client.ClientName
+	if name == "" {
+		name = client.ClientID
+	}
+	HTMLResponse(ctx, code, loginTemplate, map[string]any{
+		"ClientName": name,
+		"Error":      message,
+		"Action":     ctx.Request.URL.Path,
+		"Request":    req,
+		"CSRFToken":  csrfToken(ctx),
+	})
+}
+
+// authorizeErrorData converts an error into the oauth2 error parameters
+func (h *Handler) authorizeErrorData(err error) map[string]interface{} {
+	data, _, _ := h.srv.GetErrorData(err)
+	return data
+}
+
+// authorizeRedirect sends the user agent back to the client redirect uri
+func (h *Handler) authorizeRedirect(ctx *gin.Context, ar *server.AuthorizeRequest, data map[string]interface{}) {
+	uri, err := h.srv.GetRedirectURI(ar, data)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	ctx.Redirect(http.StatusFound, uri)
+}
+
+// clientCredentials reads the client secret or client assertion from basic authorization or the form
+func clientCredentials(r *http.Request) *domain.ClientCredentials {
+	return util.ParseClientCredentials(r)
+}
+
+// tokenError writes an oauth2 error response for the token endpoint
+func (h *Handler) tokenError(ctx *gin.Context, err error) {
+	data, code, header := h.srv.GetErrorData(err)
+	for key := range header {
+		ctx.Header(key, header.Get(key))
+	}
+	h.tokenResponse(ctx, code, data)
+}
+
+// tokenResponse writes a token endpoint response that must not be cached
+func (h *Handler) tokenResponse(ctx *gin.Context, code int, data map[string]interface{}) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Pragma", "no-cache")
+	ctx.JSON(code, data)
+}
+
+// UserInfo 		godoc
+// @Summary			UserInfo endpoint
+// @Description		Return the claims of the signed in user that the scopes of the access token allow
+// @Tags			Connect
+// @Produce			json
+// @Security		BearerAuth
+// @Success			200
+// @Router			/connect/userinfo 	[get]
+func (h *Handler) UserInfo(ctx *gin.Context) {
+	ti := getAccessTokenInfo(ctx)
+	if ti.GetUserID() == "" {
+		bearerError(ctx, http.StatusUnauthorized, "invalid_token")
+		return
+	}
+	if !domain.HasScope(ti.GetScope(), domain.ScopeOpenID) {
+		bearerError(ctx, http.StatusForbidden, "insufficient_scope")
+		return
+	}
+	result, err := h.svc.GetUserInfo(ctx, ti.GetUserID(), ti.GetScope())
+	if err != nil {
+		bearerError(ctx, http.StatusUnauthorized, "invalid_token")
+		return
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, result)
+}
+
+// bearerError writes an RFC 6750 error for a protected resource
+func bearerError(ctx *gin.Context, code int, reason string) {
+	ctx.Header("WWW-Authenticate", `Bearer error="`+reason+`"`)
+	ctx.AbortWithStatusJSON(code, gin.H{"error": reason})
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"gopkg.in/oauth2.v3/errors"
+	"gopkg.in/oauth2.v3/server"
+)
+
+// ListConsents 	godoc
+// @Summary			List consents
+// @Description		List the clients the signed in user consented to and the scopes they were granted
+// @Tags			Users
+// @Produce			json
+// @Success			200 {object} []domain.UserConsentResponse
+// @Router			/users/consents [get]
+// @Security		BearerAuth
+func (h *Handler) ListConsents(ctx *gin.Context) {
+	payload := getAuthPayload(ctx, authorizationPayloadKey)
+	result, err := h.svc.ListConsents(ctx, strconv.FormatUint(payload.UserID, 10))
+	if err != nil {
+		ErrorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// WithdrawConsent 	godoc
+// @Summary			Withdraw consent
+// @Description		Withdraw the consent of the signed in user for a client and revoke the tokens it was issued for the user
+// @Tags			Users
+// @Produce			json
+// @Param			client_id	path		string		true		"Client id"
+// @Success			200
+// @Router			/users/consents/{client_id} [delete]
+// @Security		BearerAuth
+func (h *Handler) WithdrawConsent(ctx *gin.Context) {
+	payload := getAuthPayload(ctx, authorizationPayloadKey)
+	err := h.svc.WithdrawConsent(ctx, strconv.FormatUint(payload.UserID, 10), ctx.Param("client_id"))
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			ErrorResponse(ctx, http.StatusNotFound, err)
+			return
+		}
+		ErrorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	SuccessResponse(ctx, nil)
+}
+
+// authorizeConsent returns the scope the user consented to for the authorization request. The
+// consent page is rendered until the user posts a decision, and a refusal is sent back to the
+// client as access_denied. ok is false when a response has been written.
+func (h *Handler) authorizeConsent(ctx *gin.Context, client *domain.Client, session *domain.UserSession, req *domain.AuthorizeRequest, ar *server.AuthorizeRequest) (string, bool) {
+	consent, err := h.svc.GetConsent(ctx, session.SubjectID, client, req.Scope)
+	if err != nil {
+		h.authorizeRedirect(ctx, ar, h.authorizeErrorData(err))
+		return "", false
+	}
+	if consent == nil {
+		return req.Scope, true
+	}
+	// The decision is only accepted from the posted consent page, never from a link or another site
+	if ctx.Request.Method != http.MethodPost || req.Consent == "" {
+		h.consentPage(ctx, http.StatusOK, client, consent, req)
+		return "", false
+	}
+	if !validCSRFToken(ctx) {
+		h.consentPage(ctx, http.StatusForbidden, client, consent, req)
+		return "", false
+	}
+	if req.Consent != domain.ConsentAllow {
+		h.authorizeRedirect(ctx, ar, h.authorizeErrorData(errors.ErrAccessDenied))
+		return "", false
+	}
+	scope, err := h.svc.SaveConsent(ctx, session.SubjectID, client, req.Scope, req.ConsentScopes)
+	if err != nil {
+		h.authorizeRedirect(ctx, ar, h.authorizeErrorData(err))
+		return "", false
+	}
+	return scope, true
+}
+
+// consentPage renders the scopes a client requests for the user to consent to
+func (h *Handler) consentPage(ctx *gin.Context, code int, client *domain.Client, consent *domain.Consent, req *domain.AuthorizeRequest) {
+	name := client.ClientName
+	if name == "" {
+		name = client.ClientID
+	}
+	HTMLResponse(ctx, code, consentTemplate, map[string]any{
+		"ClientName": name,
+		"ClientUri":  client.ClientUri,
+		"Consent":    consent,
+		"Action":     ctx.Request.URL.Path,
+		"Request":    req,
+		"CSRFToken":  csrfToken(ctx),
+	})
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"gopkg.in/oauth2.v3"
+	"gopkg.in/oauth2.v3/manage"
+)
+
+// DeviceAuthorization godoc
+// @Summary			Device authorization endpoint
+// @Description		Issue a device code and a user code for a device without a browser
+// @Tags			Connect
+// @Accept			x-www-form-urlencoded
+// @Produce			json
+// @Param			client_id		formData	string		false		"Client id"
+// @Param			client_secret	formData	string		false		"Client secret, omitted by public clients"
+// @Param			scope			formData	string		false		"Space separated scopes"
+// @Success			200 {object} domain.DeviceAuthorizationResponse
+// @Router			/connect/deviceauthorization 	[post]
+func (h *Handler) DeviceAuthorization(ctx *gin.Context) {
+	creds := clientCredentials(ctx.Request)
+	clientID := creds.ClientID
+	if err := h.svc.AuthenticateClient(ctx, creds); err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	result, err := h.svc.CreateDeviceAuthorization(ctx, clientID, ctx.PostForm("scope"))
+	if err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	result.VerificationUri = h.config.Issuer + "/connect/device"
+	result.VerificationUriComplete = result.VerificationUri + "?" + url.Values{"user_code": {result.UserCode}}.Encode()
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, result)
+}
+
+// DeviceVerification godoc
+// @Summary			Device verification page
+// @Description		Sign the user in and approve or deny the device behind a user code
+// @Tags			Connect
+// @Accept			x-www-form-urlencoded
+// @Produce			html
+// @Param			user_code		query		string		false		"User code shown on the device"
+// @Success			200
+// @Router			/connect/device 	[get]
+func (h *Handler) DeviceVerification(ctx *gin.Context) {
+	var req domain.DeviceVerifyRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	page := map[string]any{
+		"Action":    ctx.Request.URL.Path,
+		"Request":   &req,
+		"CSRFToken": csrfToken(ctx),
+	}
+	if req.UserCode != "" {
+		client, data, err := h.svc.GetDeviceAuthorization(ctx, req.UserCode)
+		if err != nil {
+			page["Error"] = "The code is invalid or has expired."
+			HTMLResponse(ctx, http.StatusBadRequest, deviceTemplate, page)
+			return
+		}
+		page["ClientName"] = client.ClientName
+		page["Scope"] = data.Scope
+	}
+	if ctx.Request.Method != http.MethodPost || req.UserCode == "" || req.Email == "" {
+		HTMLResponse(ctx, http.StatusOK, deviceTemplate, page)
+		return
+	}
+	if !validCSRFToken(ctx) {
+		page["Error"] = csrfError
+		HTMLResponse(ctx, http.StatusForbidden, deviceTemplate, page)
+		return
+	}
+	user, err := h.svc.LoginUser(ctx, &domain.LoginRequest{
+		Email:    req.Email,
+		Password: req.Password,
+	})
+	if err != nil {
+		page["Error"] = loginError(err)
+		HTMLResponse(ctx, http.StatusUnauthorized, deviceTemplate, page)
+		return
+	}
+	approved := req.Action != "deny"
+	err = h.svc.CompleteDeviceAuthorization(ctx, req.UserCode, strconv.FormatUint(uint64(user.ID), 10), approved)
+	if err != nil {
+		page["Error"] = "The code is invalid or has expired."
+		HTMLResponse(ctx, http.StatusBadRequest, deviceTemplate, page)
+		return
+	}
+	page["Done"] = "Device connected"
+	if !approved {
+		page["Done"] = "Device access denied"
+	}
+	HTMLResponse(ctx, http.StatusOK, deviceTemplate, page)
+}
+
+// deviceToken redeems an approved device code at the token endpoint. The device grant has
+// no manager config, so access tokens get the authorization code lifetime and no refresh token.
+func (h *Handler) deviceToken(ctx *gin.Context) {
+	creds := clientCredentials(ctx.Request)
+	clientID := creds.ClientID
+	if err := h.svc.AuthenticateClient(ctx, creds); err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	data, err := h.svc.PollDeviceCode(ctx, clientID, ctx.Request.FormValue("device_code"))
+	if err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	ti, err := h.srv.Manager.GenerateAccessToken(oauth2.GrantType(domain.GrantTypeDeviceCode), &oauth2.TokenGenerateRequest{
+		ClientID:       clientID,
+		UserID:         data.SubjectID,
+		Scope:          data.Scope,
+		AccessTokenExp: manage.DefaultAuthorizeCodeTokenCfg.AccessTokenExp,
+		Request:        ctx.Request,
+	})
+	if err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	h.issueToken(ctx, ti, &domain.AuthorizationCode{AuthTime: data.AuthTime})
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
+)
+
+// Discovery 		godoc
+// @Summary			OpenID Connect discovery document
+// @Description		Server metadata built from the registered routes and the scopes shown in the discovery document
+// @Tags			Connect
+// @Produce			json
+// @Success			200		{object}	domain.DiscoveryDocument
+// @Router			/.well-known/openid-configuration 	[get]
+func (h *Handler) Discovery(ctx *gin.Context) {
+	scopes, claims, err := h.svc.ListDiscoveryScopes(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	jwks, err := h.svc.GetJWKS(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	issuer := h.config.Issuer
+	doc := &domain.DiscoveryDocument{
+		Issuer:                                     issuer,
+		ScopesSupported:                            scopes,
+		ClaimsSupported:                            claims,
+		GrantTypesSupported:                        []string{},
+		ResponseTypesSupported:                     []string{},
+		ResponseModesSupported:                     []string{"query"},
+		SubjectTypesSupported:                      []string{"public"},
+		TokenEndpointAuthMethodsSupported:          []string{"client_secret_basic", "client_secret_post", "client_secret_jwt", "private_key_jwt", "none"},
+		TokenEndpointAuthSigningAlgValuesSupported: []string{"HS256", domain.AlgorithmRS256, domain.AlgorithmES256, domain.AlgorithmEdDSA},
+		CodeChallengeMethodsSupported:              []string{util.CodeChallengeS256},
+		TLSClientCertificateBoundAccessTokens:      h.config.TLSCertFile != "",
+		IDTokenSigningAlgValuesSupported:           []string{},
+		DPoPSigningAlgValuesSupported:              util.DPoPAlgorithms,
+	}
+	// Client certificates are only requested when the server terminates TLS itself
+	if h.config.TLSCertFile != "" {
+		doc.TokenEndpointAuthMethodsSupported = append(doc.TokenEndpointAuthMethodsSupported, "tls_client_auth", "self_signed_tls_client_auth")
+	}
+	seen := map[string]bool{}
+	for _, key := range jwks.Keys {
+		if !seen[key.Alg] {
+			seen[key.Alg] = true
+			doc.IDTokenSigningAlgValuesSupported = append(doc.IDTokenSigningAlgValuesSupported, key.Alg)
+		}
+	}
+	for _, gt := range h.srv.Config.AllowedGrantTypes {
+		doc.GrantTypesSupported = append(doc.GrantTypesSupported, string(gt))
+	}
+	for _, rt := range h.srv.Config.AllowedResponseTypes {
+		doc.ResponseTypesSupported = append(doc.ResponseTypesSupported, string(rt))
+	}
+	for _, route := range h.router.Routes() {
+		endpoint := issuer + strings.TrimPrefix(route.Path, apiBasePath)
+		switch strings.TrimPrefix(route.Path, apiBasePath) {
+		case "/connect/authorize":
+			doc.AuthorizationEndpoint = endpoint
+		case "/connect/par":
+			doc.PushedAuthorizationRequestEndpoint = endpoint
+		case "/connect/deviceauthorization":
+			doc.DeviceAuthorizationEndpoint = endpoint
+			doc.GrantTypesSupported = append(doc.GrantTypesSupported, domain.GrantTypeDeviceCode)
+		case "/connect/token":
+			doc.TokenEndpoint = endpoint
+			doc.GrantTypesSupported = append(doc.GrantTypesSupported, domain.GrantTypeTokenExchange)
+		case "/connect/introspect":
+			doc.IntrospectionEndpoint = endpoint
+		case "/connect/revocation":
+			doc.RevocationEndpoint = endpoint
+		case "/connect/endsession":
+			doc.EndSessionEndpoint = endpoint
+			doc.FrontChannelLogoutSupported = true
+			doc.FrontChannelLogoutSessionSupported = true
+			doc.BackChannelLogoutSupported = true
+			doc.BackChannelLogoutSessionSupported = true
+		case "/connect/userinfo":
+			doc.UserinfoEndpoint = endpoint
+		case "/connect/register":
+			doc.RegistrationEndpoint = endpoint
+		case "/.well-known/jwks.json":
+			doc.JwksUri = endpoint
+		}
+	}
+	ctx.JSON(http.StatusOK, doc)
+}
+
+// JWKS 			godoc
+// @Summary			JSON Web Key Set
+// @Description		Public keys used to verify tokens signed by the server
+// @Tags			Connect
+// @Produce			json
+// @Success			200		{object}	domain.JSONWebKeySet
+// @Router			/.well-known/jwks.json 	[get]
+func (h *Handler) JWKS(ctx *gin.Context) {
+	result, err := h.svc.GetJWKS(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"gopkg.in/oauth2.v3"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+// tokenDPoPProof verifies the DPoP proof sent to the token endpoint and returns nil when the
+// request has none. A refresh token bound to a DPoP key is only accepted with a proof of that key.
+// The client is not authenticated yet, so the binding is read without the reuse detection of the
+// token store, which would let anyone revoke a token family by presenting a consumed refresh token.
+func (h *Handler) tokenDPoPProof(ctx *gin.Context) (*domain.DPoPProof, error) {
+	var proof *domain.DPoPProof
+	if len(ctx.Request.Header.Values(domain.DPoPHeader)) > 0 {
+		var err error
+		proof, err = h.svc.VerifyDPoPProof(ctx, dpopHeader(ctx.Request), ctx.Request.Method, endpointURI(h.config.Issuer, ctx.Request), "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if oauth2.GrantType(ctx.Request.FormValue("grant_type")) == oauth2.Refreshing {
+		data, err := h.svc.DescribeRefreshToken(ctx, ctx.Request.FormValue("refresh_token"))
+		if err != nil {
+			// The refresh grant reports the invalid token
+			return proof, nil
+		}
+		if cnf := data.Cnf; cnf != nil && cnf.JKT != "" && (proof == nil || proof.JKT != cnf.JKT) {
+			return nil, errors.ErrInvalidGrant
+		}
+	}
+	return proof, nil
+}
+
+// tokenData returns the token response parameters, with the DPoP token type for a token bound to a proof key
+func (h *Handler) tokenData(ctx *gin.Context, ti oauth2.TokenInfo) map[string]interface{} {
+	data := h.srv.GetTokenData(ti)
+	if domain.DPoPProofFromContext(ctx.Request.Context()) != nil {
+		data["token_type"] = domain.TokenTypeDPoP
+	}
+	return data
+}
+
+// dpopHeader returns the DPoP proof of the request, several proofs are rejected like a malformed one
+func dpopHeader(r *http.Request) string {
+	values := r.Header.Values(domain.DPoPHeader)
+	if len(values) != 1 {
+		return ""
+	}
+	return values[0]
+}
+
+// endpointURI returns the public uri of the requested endpoint, which DPoP proofs are made for
+func endpointURI(issuer string, r *http.Request) string {
+	return strings.TrimSuffix(issuer, "/") + strings.TrimPrefix(r.URL.Path, apiBasePath)
+}
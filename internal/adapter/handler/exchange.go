@@ -0,0 +1,91 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
+	"gopkg.in/oauth2.v3"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+// exchangeToken swaps a subject token, optionally acted on by an actor token, for a token
+// aimed at another audience, see RFC 8693 section 2
+func (h *Handler) exchangeToken(ctx *gin.Context) {
+	creds := clientCredentials(ctx.Request)
+	clientID := creds.ClientID
+	if err := h.svc.AuthenticateClient(ctx, creds); err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	subject, err := h.exchangeTokenInfo(ctx.Request.FormValue("subject_token"), ctx.Request.FormValue("subject_token_type"))
+	if err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	claims := tokenClaims(subject.GetAccess())
+	if claims == nil {
+		h.tokenError(ctx, errors.ErrInvalidGrant)
+		return
+	}
+	req := &domain.TokenExchangeRequest{
+		ClientID:         clientID,
+		SubjectID:        subject.GetUserID(),
+		SubjectScope:     subject.GetScope(),
+		SubjectExpiresAt: subject.GetAccessCreateAt().Add(subject.GetAccessExpiresIn()),
+		SubjectAudience:  claims.Audience,
+		SubjectCnf:       claims.Cnf,
+		SubjectActor:     claims.Act,
+		Audience:         ctx.Request.FormValue("audience"),
+		Scope:            ctx.Request.FormValue("scope"),
+	}
+	if tls := ctx.Request.TLS; tls != nil && len(tls.PeerCertificates) > 0 {
+		req.Confirmation.X5tS256 = util.CertificateThumbprint(tls.PeerCertificates[0])
+	}
+	if proof := domain.DPoPProofFromContext(ctx.Request.Context()); proof != nil {
+		req.Confirmation.JKT = proof.JKT
+	}
+	if actorToken := ctx.Request.FormValue("actor_token"); actorToken != "" {
+		actor, err := h.exchangeTokenInfo(actorToken, ctx.Request.FormValue("actor_token_type"))
+		if err != nil {
+			h.tokenError(ctx, err)
+			return
+		}
+		req.ActorID = actor.GetUserID()
+		if req.ActorID == "" {
+			req.ActorID = actor.GetClientID()
+		}
+	}
+	data, err := h.svc.ExchangeToken(ctx, req)
+	if err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	ti, err := h.srv.Manager.GenerateAccessToken(oauth2.GrantType(domain.GrantTypeTokenExchange), &oauth2.TokenGenerateRequest{
+		ClientID:       clientID,
+		UserID:         data.SubjectID,
+		Scope:          data.Scope,
+		AccessTokenExp: data.ExpiresIn,
+		Request:        ctx.Request.WithContext(domain.WithTokenExchange(ctx.Request.Context(), data)),
+	})
+	if err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	res := h.tokenData(ctx, ti)
+	res["issued_token_type"] = domain.TokenTypeAccessToken
+	h.tokenResponse(ctx, http.StatusOK, res)
+}
+
+// exchangeTokenInfo loads an active access token presented to the token exchange
+func (h *Handler) exchangeTokenInfo(token, tokenType string) (oauth2.TokenInfo, error) {
+	if token == "" || tokenType != domain.TokenTypeAccessToken {
+		return nil, errors.ErrInvalidRequest
+	}
+	ti, err := h.srv.Manager.LoadAccessToken(token)
+	if err != nil {
+		return nil, errors.ErrInvalidGrant
+	}
+	return ti, nil
+}
@@ -1 +1,17 @@
 package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"gopkg.in/oauth2.v3"
+)
+
+// getAuthPayload is a helper function to get the auth payload from the context
+func getAuthPayload(ctx *gin.Context, key string) *domain.TokenPayload {
+	return ctx.MustGet(key).(*domain.TokenPayload)
+}
+
+// getAccessTokenInfo is a helper function to get the oauth2 access token info from the context
+func getAccessTokenInfo(ctx *gin.Context) oauth2.TokenInfo {
+	return ctx.MustGet(accessTokenInfoKey).(oauth2.TokenInfo)
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+// CreateIdentityResource	godoc
+// @Summary			Add a new identity resource
+// @Description		Add an identity scope releasing the user claims of its claim types in id_tokens and at the userinfo endpoint
+// @Tags			IdentityResource
+// @Accept			json
+// @Produce			json
+// @Security 		BearerAuth
+// @Param			IdentityResourceRequest		body		domain.IdentityResourceRequest		true		"Add IdentityResource Request"
+// @Success			200							{object}	domain.IdentityResourceResponse
+// @Router			/identity-resources 		[post]
+func (h *Handler) CreateIdentityResource(ctx *gin.Context) {
+	var req *domain.IdentityResourceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CreateIdentityResource(ctx, req)
+	if err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListIdentityResource 	godoc
+// @Summary 		List identity resources
+// @Description 	List identity resources with their claims and properties
+// @Tags 			IdentityResource
+// @Accept  		json
+// @Produce  		json
+// @Security 		BearerAuth
+// @Success 		200 	{array} 	domain.IdentityResourceResponse
+// @Router 			/identity-resources 	[get]
+func (h *Handler) ListIdentityResource(ctx *gin.Context) {
+	result, err := h.svc.ListIdentityResource(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// GetIdentityResource 	godoc
+// @Summary 		Get identity resource
+// @Description 	Get an identity resource with its claims and properties from Id
+// @Tags 			IdentityResource
+// @Accept  		json
+// @Produce  		json
+// @Security 		BearerAuth
+// @Param 			id path string true "IdentityResource id"
+// @Success 		200 {object} domain.IdentityResourceResponse
+// @Router 			/identity-resources/{id} [get]
+func (h *Handler) GetIdentityResource(ctx *gin.Context) {
+	result, err := h.svc.GetIdentityResource(ctx, ctx.Param("id"))
+	if err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// UpdateIdentityResource	godoc
+// @Summary 		Update identity resource
+// @Description 	Replace an identity resource, its claims and properties included
+// @Tags 			IdentityResource
+// @Accept  		json
+// @Produce  		json
+// @Security 		BearerAuth
+// @Param 			id 									path 		string 									true 	"IdentityResource id"
+// @Param 			UpdateIdentityResourceRequest	 	body 		domain.IdentityResourceUpdateRequest 	true 	"Update IdentityResource request"
+// @Success 		200 								{object} 	domain.IdentityResourceResponse
+// @Router 			/identity-resources/{id} 			[put]
+func (h *Handler) UpdateIdentityResource(ctx *gin.Context) {
+	var req *domain.IdentityResourceUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.UpdateIdentityResource(ctx, ctx.Param("id"), req)
+	if err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// DeleteIdentityResource 	godoc
+// @Summary 		Delete identity resource
+// @Description 	Delete an identity resource with its claims and properties
+// @Tags 			IdentityResource
+// @Accept  		json
+// @Produce  		json
+// @Security 		BearerAuth
+// @Param 			id 							path 		string 		true 	"IdentityResource id"
+// @Success 		200 						{object} 	domain.IdentityResourceResponse
+// @Router 			/identity-resources/{id} 	[delete]
+func (h *Handler) DeleteIdentityResource(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.GetIdentityResource(ctx, id)
+	if err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	if err := h.svc.DeleteIdentityResource(ctx, id); err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+// Introspect 		godoc
+// @Summary			Token introspection endpoint
+// @Description		Describe an access token, refresh token or PASETO token for a client or api resource
+// @Tags			Connect
+// @Accept			x-www-form-urlencoded
+// @Produce			json
+// @Param			token			formData	string		true		"Token to introspect"
+// @Param			token_type_hint	formData	string		false		"access_token or refresh_token"
+// @Param			client_id		formData	string		false		"Client id or api resource name"
+// @Param			client_secret	formData	string		false		"Client secret or api secret"
+// @Success			200 {object} domain.IntrospectionResponse
+// @Router			/connect/introspect 	[post]
+func (h *Handler) Introspect(ctx *gin.Context) {
+	resource, err := h.svc.AuthenticateIntrospection(ctx, clientCredentials(ctx.Request))
+	if err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	token := ctx.PostForm("token")
+	if token == "" {
+		h.tokenError(ctx, errors.ErrInvalidRequest)
+		return
+	}
+	result, err := h.svc.CompleteIntrospection(ctx, resource, h.introspect(ctx, token, ctx.PostForm("token_type_hint")))
+	if err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, result)
+}
+
+// introspect looks the token up in the oauth2 token store, then as a PASETO token not on the denylist
+func (h *Handler) introspect(ctx *gin.Context, token, hint string) *domain.IntrospectionResponse {
+	loaders := []func(*gin.Context, string) *domain.IntrospectionResponse{h.introspectAccessToken, h.introspectRefreshToken}
+	if hint == "refresh_token" {
+		loaders[0], loaders[1] = loaders[1], loaders[0]
+	}
+	for _, load := range loaders {
+		if result := load(ctx, token); result != nil {
+			return result
+		}
+	}
+	payload, err := h.token.VerifyToken(token)
+	if err != nil {
+		return &domain.IntrospectionResponse{Active: false}
+	}
+	if revoked, err := h.svc.IsTokenRevoked(ctx, payload.ID); err != nil || revoked {
+		return &domain.IntrospectionResponse{Active: false}
+	}
+	result := &domain.IntrospectionResponse{
+		Active:    true,
+		Subject:   strconv.FormatUint(payload.UserID, 10),
+		TokenType: "paseto",
+	}
+	if !payload.ExpiredAt.IsZero() {
+		result.ExpiresAt = payload.ExpiredAt.Unix()
+	}
+	if !payload.IssuedAt.IsZero() {
+		result.IssuedAt = payload.IssuedAt.Unix()
+	}
+	return result
+}
+
+// introspectAccessToken describes an unexpired access token from the token store
+func (h *Handler) introspectAccessToken(ctx *gin.Context, token string) *domain.IntrospectionResponse {
+	ti, err := h.srv.Manager.LoadAccessToken(token)
+	if err != nil {
+		return nil
+	}
+	result := &domain.IntrospectionResponse{
+		Active:    true,
+		Scope:     ti.GetScope(),
+		ClientID:  ti.GetClientID(),
+		Subject:   ti.GetUserID(),
+		ExpiresAt: ti.GetAccessCreateAt().Add(ti.GetAccessExpiresIn()).Unix(),
+		IssuedAt:  ti.GetAccessCreateAt().Unix(),
+		TokenType: "Bearer",
+	}
+	// The token carries the scopes and audience of the resources it was issued for
+	if claims := tokenClaims(token); claims != nil {
+		result.Scope = claims.Scope
+		result.Audience = claims.Audience
+		result.Cnf = claims.Cnf
+	}
+	return result
+}
+
+// introspectRefreshToken describes an unconsumed, unexpired refresh token
+func (h *Handler) introspectRefreshToken(ctx *gin.Context, token string) *domain.IntrospectionResponse {
+	result, err := h.svc.DescribeRefreshToken(ctx, token)
+	if err != nil {
+		return nil
+	}
+	return result
+}
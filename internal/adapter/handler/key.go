@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListKeys 		godoc
+// @Summary			List signing keys
+// @Description		List signing keys with their lifecycle status (pending, active, retired, expired)
+// @Tags			Key
+// @Accept			json
+// @Produce			json
+// @Security		BearerAuth
+// @Success			200		{array}		domain.KeyResponse
+// @Router			/keys 	[get]
+func (h *Handler) ListKeys(ctx *gin.Context) {
+	result, err := h.svc.ListSigningKeys(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// RotateKeys 		godoc
+// @Summary			Rotate signing keys
+// @Description		Pre-publish a new signing key now. It is promoted to signing once the activation delay has passed.
+// @Tags			Key
+// @Accept			json
+// @Produce			json
+// @Security		BearerAuth
+// @Success			200		{array}		domain.KeyResponse
+// @Router			/keys/rotate 	[post]
+func (h *Handler) RotateKeys(ctx *gin.Context) {
+	err := h.svc.RotateSigningKeys(ctx, true)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	result, err := h.svc.ListSigningKeys(ctx)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
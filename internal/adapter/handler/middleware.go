@@ -2,11 +2,16 @@ package http
 
 import (
 	"net/http"
+	"slices"
 	"strings"
 
+	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"github.com/sugaml/authserver/internal/core/domain"
 	"github.com/sugaml/authserver/internal/core/port"
+	"github.com/sugaml/authserver/internal/core/util"
+	"gopkg.in/oauth2.v3"
+	"gopkg.in/oauth2.v3/server"
 )
 
 const (
@@ -16,10 +21,12 @@ const (
 	authorizationType = "bearer"
 	// authorizationPayloadKey is the key for authorization payload in the context
 	authorizationPayloadKey = "authorization_payload"
+	// accessTokenInfoKey is the key for the oauth2 access token info in the context
+	accessTokenInfoKey = "access_token_info"
 )
 
 // authMiddleware is a middleware to check if the user is authenticated
-func authMiddleware(token port.TokenService) gin.HandlerFunc {
+func authMiddleware(token port.TokenService, revocation port.RevocationService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 
@@ -27,6 +34,7 @@ func authMiddleware(token port.TokenService) gin.HandlerFunc {
 		if isEmpty {
 			err := domain.ErrEmptyAuthorizationHeader
 			ErrorResponse(ctx, http.StatusUnauthorized, err)
+			ctx.Abort()
 			return
 		}
 
@@ -35,6 +43,7 @@ func authMiddleware(token port.TokenService) gin.HandlerFunc {
 		if !isValid {
 			err := domain.ErrInvalidAuthorizationHeader
 			ErrorResponse(ctx, http.StatusUnauthorized, err)
+			ctx.Abort()
 			return
 		}
 
@@ -42,6 +51,7 @@ func authMiddleware(token port.TokenService) gin.HandlerFunc {
 		if currentAuthorizationType != authorizationType {
 			err := domain.ErrInvalidAuthorizationType
 			ErrorResponse(ctx, http.StatusUnauthorized, err)
+			ctx.Abort()
 			return
 		}
 
@@ -49,6 +59,19 @@ func authMiddleware(token port.TokenService) gin.HandlerFunc {
 		payload, err := token.VerifyToken(accessToken)
 		if err != nil {
 			ErrorResponse(ctx, http.StatusUnauthorized, err)
+			ctx.Abort()
+			return
+		}
+
+		revoked, err := revocation.IsTokenRevoked(ctx, payload.ID)
+		if err != nil {
+			ErrorResponse(ctx, http.StatusInternalServerError, err)
+			ctx.Abort()
+			return
+		}
+		if revoked {
+			ErrorResponse(ctx, http.StatusUnauthorized, domain.ErrRevokedToken)
+			ctx.Abort()
 			return
 		}
 
@@ -57,15 +80,97 @@ func authMiddleware(token port.TokenService) gin.HandlerFunc {
 	}
 }
 
-// adminMiddleware is a middleware to check if the user is an admin
-func adminMiddleware() gin.HandlerFunc {
+// accessTokenMiddleware is a middleware to check the oauth2 access token of a protected resource.
+// A token bound to a client certificate is only accepted over a connection presenting it, and a
+// token bound to a DPoP key only with a proof made with that key.
+func accessTokenMiddleware(srv *server.Server, dpop port.DPoPService, issuer string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var ti oauth2.TokenInfo
+		var err error
+		scheme, access, _ := strings.Cut(ctx.GetHeader(authorizationHeaderKey), " ")
+		if strings.EqualFold(scheme, domain.TokenTypeDPoP) {
+			ti, err = srv.Manager.LoadAccessToken(access)
+		} else {
+			ti, err = srv.ValidationBearerToken(ctx.Request)
+			access = ""
+		}
+		if err != nil {
+			bearerError(ctx, http.StatusUnauthorized, "invalid_token")
+			return
+		}
+		cnf := tokenConfirmation(ti.GetAccess())
+		if cnf == nil {
+			cnf = &domain.Confirmation{}
+		}
+		if cnf.X5tS256 != "" {
+			tls := ctx.Request.TLS
+			if tls == nil || len(tls.PeerCertificates) == 0 || util.CertificateThumbprint(tls.PeerCertificates[0]) != cnf.X5tS256 {
+				bearerError(ctx, http.StatusUnauthorized, "invalid_token")
+				return
+			}
+		}
+		// A DPoP bound token is only accepted with the DPoP scheme and a proof made with its key
+		if cnf.JKT != "" || access != "" {
+			if cnf.JKT == "" || access == "" {
+				bearerError(ctx, http.StatusUnauthorized, "invalid_token")
+				return
+			}
+			proof, err := dpop.VerifyDPoPProof(ctx, dpopHeader(ctx.Request), ctx.Request.Method, endpointURI(issuer, ctx.Request), access)
+			if err != nil || proof.JKT != cnf.JKT {
+				bearerError(ctx, http.StatusUnauthorized, "invalid_dpop_proof")
+				return
+			}
+		}
+		ctx.Set(accessTokenInfoKey, ti)
+		ctx.Next()
+	}
+}
+
+// accessTokenClaims are the claims of an access token read back by the auth server
+type accessTokenClaims struct {
+	Scope    string               `json:"scope"`
+	Audience domain.Audience      `json:"aud"`
+	Cnf      *domain.Confirmation `json:"cnf"`
+	Act      *domain.Actor        `json:"act"`
+	jwt.StandardClaims
+}
+
+// tokenClaims reads the claims of an access token already found in the token store
+func tokenClaims(access string) *accessTokenClaims {
+	claims := &accessTokenClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(access, claims); err != nil {
+		return nil
+	}
+	return claims
+}
+
+// tokenConfirmation reads the cnf claim of an access token already found in the token store
+func tokenConfirmation(access string) *domain.Confirmation {
+	claims := tokenClaims(access)
+	if claims == nil {
+		return nil
+	}
+	return claims.Cnf
+}
+
+// adminMiddleware is a middleware to check if the user is an admin, it must follow authMiddleware.
+// Roles are read on every request rather than from the token, so removing the admin role takes
+// effect immediately.
+func adminMiddleware(users port.UserService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		// payload := getAuthPayload(ctx, authorizationPayloadKey)
+		payload := getAuthPayload(ctx, authorizationPayloadKey)
 
-		isAdmin := true
+		roles, err := users.GetUserRoles(ctx, payload.UserID)
+		if err != nil {
+			ErrorResponse(ctx, http.StatusInternalServerError, err)
+			ctx.Abort()
+			return
+		}
+		isAdmin := slices.Contains(roles, domain.RoleAdmin)
 		if !isAdmin {
 			err := domain.ErrForbidden
-			ErrorResponse(ctx, http.StatusUnauthorized, err)
+			ErrorResponse(ctx, http.StatusForbidden, err)
+			ctx.Abort()
 			return
 		}
 
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"bytes"
+	"html/template"
+
+	"github.com/gin-gonic/gin"
+)
+
+// loginTemplate renders the sign in form of the authorization endpoint
+var loginTemplate = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Sign in</title></head>
+<body>
+	<h2>Sign in to {{.ClientName}}</h2>
+	{{if .Error}}<p style="color:red">{{.Error}}</p>{{end}}
+	<form method="post" action="{{.Action}}">
+		<input type="hidden" name="csrf_token" value="{{.CSRFToken}}">
+		<input type="hidden" name="client_id" value="{{.Request.ClientID}}">
+		{{if .Request.RequestURI}}
+		<input type="hidden" name="request_uri" value="{{.Request.RequestURI}}">
+		{{else}}
+		<input type="hidden" name="response_type" value="{{.Request.ResponseType}}">
+		<input type="hidden" name="redirect_uri" value="{{.Request.RedirectURI}}">
+		<input type="hidden" name="scope" value="{{.Request.Scope}}">
+		<input type="hidden" name="state" value="{{.Request.State}}">
+		<input type="hidden" name="nonce" value="{{.Request.Nonce}}">
+		<input type="hidden" name="code_challenge" value="{{.Request.CodeChallenge}}">
+		<input type="hidden" name="code_challenge_method" value="{{.Request.CodeChallengeMethod}}">
+		{{end}}
+		<label>Email <input type="email" name="email" value="{{.Request.Email}}" required></label><br>
+		<label>Password <input type="password" name="password" required></label><br>
+		<button type="submit">Sign in</button>
+	</form>
+</body>
+</html>`))
+
+// consentTemplate renders the scopes requested by a client, required scopes cannot be unchecked
+var consentTemplate = template.Must(template.New("consent").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Consent</title></head>
+<body>
+	<h2>{{if .ClientUri}}<a href="{{.ClientUri}}">{{.ClientName}}</a>{{else}}{{.ClientName}}{{end}} is requesting your permission</h2>
+	<form method="post" action="{{.Action}}">
+		<input type="hidden" name="csrf_token" value="{{.CSRFToken}}">
+		<input type="hidden" name="client_id" value="{{.Request.ClientID}}">
+		{{if .Request.RequestURI}}
+		<input type="hidden" name="request_uri" value="{{.Request.RequestURI}}">
+		{{else}}
+		<input type="hidden" name="response_type" value="{{.Request.ResponseType}}">
+		<input type="hidden" name="redirect_uri" value="{{.Request.RedirectURI}}">
+		<input type="hidden" name="scope" value="{{.Request.Scope}}">
+		<input type="hidden" name="state" value="{{.Request.State}}">
+		<input type="hidden" name="nonce" value="{{.Request.Nonce}}">
+		<input type="hidden" name="code_challenge" value="{{.Request.CodeChallenge}}">
+		<input type="hidden" name="code_challenge_method" value="{{.Request.CodeChallengeMethod}}">
+		{{end}}
+		{{if .Consent.IdentityScopes}}
+		<h3>Personal information</h3>
+		{{range .Consent.IdentityScopes}}{{template "scope" .}}{{end}}
+		{{end}}
+		{{if .Consent.ApiScopes}}
+		<h3>Application access</h3>
+		{{range .Consent.ApiScopes}}{{template "scope" .}}{{end}}
+		{{end}}
+		<button type="submit" name="consent" value="allow">Allow</button>
+		<button type="submit" name="consent" value="deny">Deny</button>
+	</form>
+</body>
+</html>
+{{define "scope"}}
+		<div>
+			<label{{if .Emphasize}} style="font-weight:bold"{{end}}>
+				{{if .Required}}
+				<input type="checkbox" checked disabled>
+				<input type="hidden" name="consent_scopes" value="{{.Name}}">
+				{{else}}
+				<input type="checkbox" name="consent_scopes" value="{{.Name}}"{{if .Checked}} checked{{end}}>
+				{{end}}
+				{{.DisplayName}}{{if .Required}} (required){{end}}
+			</label>
+			{{if .Description}}<div>{{.Description}}</div>{{end}}
+		</div>
+{{end}}`))
+
+// deviceTemplate renders the verification page a user signs in on to approve a device
+var deviceTemplate = template.Must(template.New("device").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Connect a device</title></head>
+<body>
+	{{if .Done}}
+	<h2>{{.Done}}</h2>
+	<p>You can return to your device.</p>
+	{{else}}
+	<h2>{{if .ClientName}}Connect {{.ClientName}}{{else}}Connect a device{{end}}</h2>
+	{{if .Scope}}<p>The device is requesting: {{.Scope}}</p>{{end}}
+	{{if .Error}}<p style="color:red">{{.Error}}</p>{{end}}
+	<form method="post" action="{{.Action}}">
+		<input type="hidden" name="csrf_token" value="{{.CSRFToken}}">
+		<label>Code <input type="text" name="user_code" value="{{.Request.UserCode}}" required></label><br>
+		<label>Email <input type="email" name="email" value="{{.Request.Email}}" required></label><br>
+		<label>Password <input type="password" name="password" required></label><br>
+		<button type="submit" name="action" value="approve">Allow</button>
+		<button type="submit" name="action" value="deny">Deny</button>
+	</form>
+	{{end}}
+</body>
+</html>`))
+
+// logoutTemplate renders the signed out page, loading the front-channel logout uri of every client
+// before following the post logout redirect uri
+var logoutTemplate = template.Must(template.New("logout").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Signed out</title></head>
+<body>
+	<h2>You are signed out</h2>
+	{{range .FrontChannelLogoutUris}}<iframe src="{{.}}" style="display:none" onload="loaded()"></iframe>{{end}}
+	{{if .RedirectURI}}
+	<p><a href="{{.RedirectURI}}">Continue</a></p>
+	<script>
+		var pending = {{len .FrontChannelLogoutUris}};
+		function done() { window.location.href = {{.RedirectURI}}; }
+		function loaded() { if (--pending <= 0) done(); }
+		setTimeout(done, 3000);
+	</script>
+	{{else}}
+	<script>function loaded() {}</script>
+	{{end}}
+</body>
+</html>`))
+
+// HTMLResponse renders an html template with the given status code
+func HTMLResponse(ctx *gin.Context, code int, tmpl *template.Template, data any) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		ctx.String(code, err.Error())
+		return
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Data(code, "text/html; charset=utf-8", buf.Bytes())
+}
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+// PushedAuthorization godoc
+// @Summary			Pushed authorization request endpoint
+// @Description		Store the authorization parameters of an authenticated client and return a request_uri for /connect/authorize
+// @Tags			Connect
+// @Accept			x-www-form-urlencoded
+// @Produce			json
+// @Param			client_id				formData	string		true		"Client id"
+// @Param			client_secret			formData	string		false		"Client secret, omitted by public clients"
+// @Param			response_type			formData	string		true		"Must be code"
+// @Param			redirect_uri			formData	string		true		"Registered redirect uri"
+// @Param			scope					formData	string		false		"Space separated scopes"
+// @Param			state					formData	string		false		"Opaque client state"
+// @Param			nonce					formData	string		false		"OpenID Connect nonce"
+// @Param			code_challenge			formData	string		false		"PKCE code challenge"
+// @Param			code_challenge_method	formData	string		false		"PKCE method, must be S256"
+// @Success			201 {object} domain.PushedAuthorizationResponse
+// @Router			/connect/par 	[post]
+func (h *Handler) PushedAuthorization(ctx *gin.Context) {
+	creds := clientCredentials(ctx.Request)
+	if err := h.svc.AuthenticateClient(ctx, creds); err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	var req domain.AuthorizeRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		h.tokenError(ctx, errors.ErrInvalidRequest)
+		return
+	}
+	if req.ClientID != "" && req.ClientID != creds.ClientID {
+		h.tokenError(ctx, errors.ErrInvalidRequest)
+		return
+	}
+	req.ClientID = creds.ClientID
+	result, err := h.svc.PushAuthorizationRequest(ctx, &req)
+	if err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusCreated, result)
+}
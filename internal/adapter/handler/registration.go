@@ -0,0 +1,121 @@
+package http
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+// RegisterClient godoc
+// @Summary			Dynamic client registration endpoint
+// @Description		Register a client from its metadata, authorized by the bearer access token of a signed in user, see RFC 7591
+// @Tags			Connect
+// @Accept			json
+// @Produce			json
+// @Security		ApiKeyAuth
+// @Param			request		body		domain.ClientRegistrationRequest	true	"Client metadata"
+// @Success			201 {object} domain.ClientRegistrationResponse
+// @Router			/connect/register 	[post]
+func (h *Handler) RegisterClient(ctx *gin.Context) {
+	var req domain.ClientRegistrationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.registrationError(ctx, &domain.ClientMetadataError{Code: "invalid_client_metadata", Description: err.Error()})
+		return
+	}
+	result, err := h.svc.RegisterClient(ctx, &req)
+	if err != nil {
+		h.registrationError(ctx, err)
+		return
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusCreated, result)
+}
+
+// GetClientRegistration godoc
+// @Summary			Read a dynamically registered client
+// @Description		Return the metadata of the client, authorized by its registration access token, see RFC 7592
+// @Tags			Connect
+// @Produce			json
+// @Param			client_id		path		string		true		"Client id"
+// @Param			Authorization	header		string		true		"Bearer registration access token"
+// @Success			200 {object} domain.ClientRegistrationResponse
+// @Router			/connect/register/{client_id} 	[get]
+func (h *Handler) GetClientRegistration(ctx *gin.Context) {
+	result, err := h.svc.GetClientRegistration(ctx, ctx.Param("client_id"), registrationToken(ctx))
+	if err != nil {
+		h.registrationError(ctx, err)
+		return
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, result)
+}
+
+// UpdateClientRegistration godoc
+// @Summary			Update a dynamically registered client
+// @Description		Replace the metadata of the client, authorized by its registration access token, and rotate the token, see RFC 7592
+// @Tags			Connect
+// @Accept			json
+// @Produce			json
+// @Param			client_id		path		string								true	"Client id"
+// @Param			Authorization	header		string								true	"Bearer registration access token"
+// @Param			request			body		domain.ClientRegistrationRequest	true	"Client metadata"
+// @Success			200 {object} domain.ClientRegistrationResponse
+// @Router			/connect/register/{client_id} 	[put]
+func (h *Handler) UpdateClientRegistration(ctx *gin.Context) {
+	var req domain.ClientRegistrationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		h.registrationError(ctx, &domain.ClientMetadataError{Code: "invalid_client_metadata", Description: err.Error()})
+		return
+	}
+	result, err := h.svc.UpdateClientRegistration(ctx, ctx.Param("client_id"), registrationToken(ctx), &req)
+	if err != nil {
+		h.registrationError(ctx, err)
+		return
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, result)
+}
+
+// DeleteClientRegistration godoc
+// @Summary			Delete a dynamically registered client
+// @Description		Delete the client and invalidate its registration access token, see RFC 7592
+// @Tags			Connect
+// @Param			client_id		path		string		true		"Client id"
+// @Param			Authorization	header		string		true		"Bearer registration access token"
+// @Success			204
+// @Router			/connect/register/{client_id} 	[delete]
+func (h *Handler) DeleteClientRegistration(ctx *gin.Context) {
+	if err := h.svc.DeleteClientRegistration(ctx, ctx.Param("client_id"), registrationToken(ctx)); err != nil {
+		h.registrationError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// registrationToken returns the bearer registration access token of the request
+func registrationToken(ctx *gin.Context) string {
+	fields := strings.Fields(ctx.GetHeader(authorizationHeaderKey))
+	if len(fields) != 2 || strings.ToLower(fields[0]) != authorizationType {
+		return ""
+	}
+	return fields[1]
+}
+
+// registrationError writes the client metadata errors of RFC 7591 section 3.2.2 and a bearer
+// challenge for a missing or unknown registration access token
+func (h *Handler) registrationError(ctx *gin.Context, err error) {
+	var metadata *domain.ClientMetadataError
+	switch {
+	case stderrors.As(err, &metadata):
+		ctx.Header("Cache-Control", "no-store")
+		ctx.JSON(http.StatusBadRequest, metadata)
+	case err == errors.ErrInvalidAccessToken:
+		bearerError(ctx, http.StatusUnauthorized, "invalid_token")
+	default:
+		h.tokenError(ctx, err)
+	}
+}
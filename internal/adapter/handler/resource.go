@@ -0,0 +1,343 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+// CreateResource	godoc
+// @Summary			Add a new api resource
+// @Description		Add a new api resource. Its name is the audience of the access tokens issued for it
+// @Tags			Resource
+// @Accept			json
+// @Produce			json
+// @Security 		BearerAuth
+// @Param			ResourceRequest		body		domain.ResourceRequest		true		"Add Resource Request"
+// @Success			200					{object}	domain.ResourceResponse
+// @Router			/resources 			[post]
+func (h *Handler) CreateResource(ctx *gin.Context) {
+	var req *domain.ResourceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CreateResource(ctx, req)
+	if err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListResource 	godoc
+// @Summary 		List api resources
+// @Description 	List api resources, optionally filtered by name
+// @Tags 			Resource
+// @Accept  		json
+// @Produce  		json
+// @Security 		BearerAuth
+// @Param 			name 		query 		string 		false 		"Name filter"
+// @Success 		200 		{array} 	domain.ResourceResponse
+// @Router 			/resources 	[get]
+func (h *Handler) ListResource(ctx *gin.Context) {
+	req := domain.ResourceListRequest{Name: ctx.Query("name")}
+	result, count, err := h.svc.ListResource(ctx, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	SuccessResponse(ctx, result, WithPagination(count, 0, 0))
+}
+
+// GetResource 		godoc
+// @Summary 		Get api resource
+// @Description 	Get an api resource with its scopes from Id
+// @Tags 			Resource
+// @Accept  		json
+// @Produce  		json
+// @Security 		BearerAuth
+// @Param 			id path string true "Resource id"
+// @Success 		200 {object} domain.ResourceResponse
+// @Router 			/resources/{id} [get]
+func (h *Handler) GetResource(ctx *gin.Context) {
+	result, err := h.svc.GetResource(ctx, ctx.Param("id"))
+	if err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// UpdateResource	godoc
+// @Summary 		Update api resource
+// @Description 	Update api resource from Id
+// @Tags 			Resource
+// @Accept  		json
+// @Produce  		json
+// @Security 		BearerAuth
+// @Param 			id 							path 		string 							true 	"Resource id"
+// @Param 			UpdateResourceRequest	 	body 		domain.ResourceUpdateRequest 	true 	"Update Resource request"
+// @Success 		200 						{object} 	domain.ResourceResponse
+// @Router 			/resources/{id} 			[put]
+func (h *Handler) UpdateResource(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req *domain.ResourceUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if _, err := h.svc.UpdateResource(ctx, id, req); err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	result, err := h.svc.GetResource(ctx, id)
+	if err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// DeleteResource 	godoc
+// @Summary 		Delete api resource
+// @Description 	Delete an api resource with its scopes, scope claims and secrets
+// @Tags 			Resource
+// @Accept  		json
+// @Produce  		json
+// @Security 		BearerAuth
+// @Param 			id 					path 		string 		true 	"Resource id"
+// @Success 		200 				{object} 	domain.ResourceResponse
+// @Router 			/resources/{id} 	[delete]
+func (h *Handler) DeleteResource(ctx *gin.Context) {
+	id := ctx.Param("id")
+	result, err := h.svc.GetResource(ctx, id)
+	if err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	if err := h.svc.DeleteResource(ctx, id); err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// CreateApiScope	godoc
+// @Summary			Add a scope to an api resource
+// @Description		Add a scope with the user claims it adds to tokens. Scope names are unique across resources
+// @Tags			Resource
+// @Accept			json
+// @Produce			json
+// @Security 		BearerAuth
+// @Param 			id 					path 		string 					true 	"Resource id"
+// @Param			ApiScopeRequest		body		domain.ApiScopeRequest	true	"Add ApiScope Request"
+// @Success			200					{object}	domain.ApiScopeResponse
+// @Router			/resources/{id}/scopes 		[post]
+func (h *Handler) CreateApiScope(ctx *gin.Context) {
+	var req *domain.ApiScopeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CreateApiScope(ctx, ctx.Param("id"), req)
+	if err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListApiScope 	godoc
+// @Summary 		List the scopes of an api resource
+// @Description 	List the scopes of an api resource with their claims
+// @Tags 			Resource
+// @Accept  		json
+// @Produce  		json
+// @Security 		BearerAuth
+// @Param 			id 		path 		string 		true 	"Resource id"
+// @Success 		200 	{array} 	domain.ApiScopeResponse
+// @Router 			/resources/{id}/scopes 	[get]
+func (h *Handler) ListApiScope(ctx *gin.Context) {
+	result, err := h.svc.ListApiScope(ctx, ctx.Param("id"))
+	if err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// GetApiScope 		godoc
+// @Summary 		Get a scope of an api resource
+// @Description 	Get a scope of an api resource with its claims
+// @Tags 			Resource
+// @Accept  		json
+// @Produce  		json
+// @Security 		BearerAuth
+// @Param 			id 			path 		string 		true 	"Resource id"
+// @Param 			scope_id 	path 		string 		true 	"ApiScope id"
+// @Success 		200 		{object} 	domain.ApiScopeResponse
+// @Router 			/resources/{id}/scopes/{scope_id} 	[get]
+func (h *Handler) GetApiScope(ctx *gin.Context) {
+	result, err := h.svc.GetApiScope(ctx, ctx.Param("id"), ctx.Param("scope_id"))
+	if err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// UpdateApiScope	godoc
+// @Summary 		Update a scope of an api resource
+// @Description 	Replace a scope of an api resource, its claims included
+// @Tags 			Resource
+// @Accept  		json
+// @Produce  		json
+// @Security 		BearerAuth
+// @Param 			id 							path 		string 							true 	"Resource id"
+// @Param 			scope_id 					path 		string 							true 	"ApiScope id"
+// @Param 			UpdateApiScopeRequest	 	body 		domain.ApiScopeUpdateRequest 	true 	"Update ApiScope request"
+// @Success 		200 						{object} 	domain.ApiScopeResponse
+// @Router 			/resources/{id}/scopes/{scope_id} 	[put]
+func (h *Handler) UpdateApiScope(ctx *gin.Context) {
+	var req *domain.ApiScopeUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.UpdateApiScope(ctx, ctx.Param("id"), ctx.Param("scope_id"), req)
+	if err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// DeleteApiScope 	godoc
+// @Summary 		Delete a scope of an api resource
+// @Description 	Delete a scope of an api resource with its claims
+// @Tags 			Resource
+// @Accept  		json
+// @Produce  		json
+// @Security 		BearerAuth
+// @Param 			id 			path 		string 		true 	"Resource id"
+// @Param 			scope_id 	path 		string 		true 	"ApiScope id"
+// @Success 		200 		{object} 	domain.ApiScopeResponse
+// @Router 			/resources/{id}/scopes/{scope_id} 	[delete]
+func (h *Handler) DeleteApiScope(ctx *gin.Context) {
+	id, scopeID := ctx.Param("id"), ctx.Param("scope_id")
+	result, err := h.svc.GetApiScope(ctx, id, scopeID)
+	if err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	if err := h.svc.DeleteApiScope(ctx, id, scopeID); err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// CreateApiSecret	godoc
+// @Summary			Add a secret to an api resource
+// @Description		Add a secret the api resource authenticates with at the introspection endpoint. The value is generated and returned only in this response
+// @Tags			Resource
+// @Accept			json
+// @Produce			json
+// @Security 		BearerAuth
+// @Param 			id 					path 		string 						true 	"Resource id"
+// @Param			ApiSecretRequest	body		domain.ApiSecretRequest		true	"Add ApiSecret Request"
+// @Success			200					{object}	domain.ApiSecretResponse
+// @Router			/resources/{id}/secrets 	[post]
+func (h *Handler) CreateApiSecret(ctx *gin.Context) {
+	var req *domain.ApiSecretRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.CreateApiSecret(ctx, ctx.Param("id"), req)
+	if err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// ListApiSecret 	godoc
+// @Summary 		List the secrets of an api resource
+// @Description 	List the secrets of an api resource without their values
+// @Tags 			Resource
+// @Accept  		json
+// @Produce  		json
+// @Security 		BearerAuth
+// @Param 			id 		path 		string 		true 	"Resource id"
+// @Success 		200 	{array} 	domain.ApiSecretResponse
+// @Router 			/resources/{id}/secrets 	[get]
+func (h *Handler) ListApiSecret(ctx *gin.Context) {
+	result, err := h.svc.ListApiSecret(ctx, ctx.Param("id"))
+	if err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// UpdateApiSecret	godoc
+// @Summary 		Update a secret of an api resource
+// @Description 	Update the description and expiration of a secret of an api resource
+// @Tags 			Resource
+// @Accept  		json
+// @Produce  		json
+// @Security 		BearerAuth
+// @Param 			id 							path 		string 							true 	"Resource id"
+// @Param 			secret_id 					path 		string 							true 	"ApiSecret id"
+// @Param 			UpdateApiSecretRequest	 	body 		domain.ApiSecretUpdateRequest 	true 	"Update ApiSecret request"
+// @Success 		200 						{object} 	domain.ApiSecretResponse
+// @Router 			/resources/{id}/secrets/{secret_id} 	[put]
+func (h *Handler) UpdateApiSecret(ctx *gin.Context) {
+	var req *domain.ApiSecretUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	result, err := h.svc.UpdateApiSecret(ctx, ctx.Param("id"), ctx.Param("secret_id"), req)
+	if err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, result)
+}
+
+// DeleteApiSecret 	godoc
+// @Summary 		Delete a secret of an api resource
+// @Description 	Delete a secret of an api resource
+// @Tags 			Resource
+// @Accept  		json
+// @Produce  		json
+// @Security 		BearerAuth
+// @Param 			id 			path 		string 		true 	"Resource id"
+// @Param 			secret_id 	path 		string 		true 	"ApiSecret id"
+// @Success 		200
+// @Router 			/resources/{id}/secrets/{secret_id} 	[delete]
+func (h *Handler) DeleteApiSecret(ctx *gin.Context) {
+	if err := h.svc.DeleteApiSecret(ctx, ctx.Param("id"), ctx.Param("secret_id")); err != nil {
+		resourceError(ctx, err)
+		return
+	}
+	SuccessResponse(ctx, nil)
+}
+
+// resourceError maps missing and conflicting data to 404 and 409, validation errors to 400
+func resourceError(ctx *gin.Context, err error) {
+	switch err {
+	case domain.ErrDataNotFound:
+		ErrorResponse(ctx, http.StatusNotFound, err)
+	case domain.ErrConflictingData:
+		ErrorResponse(ctx, http.StatusConflict, err)
+	case domain.ErrInternal:
+		ErrorResponse(ctx, http.StatusInternalServerError, err)
+	default:
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+	}
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+// Revocation 		godoc
+// @Summary			Token revocation endpoint
+// @Description		Revoke an access token, refresh token or PASETO token
+// @Tags			Connect
+// @Accept			x-www-form-urlencoded
+// @Produce			json
+// @Param			token			formData	string		true		"Token to revoke"
+// @Param			token_type_hint	formData	string		false		"access_token or refresh_token"
+// @Param			client_id		formData	string		false		"Client id"
+// @Param			client_secret	formData	string		false		"Client secret, omitted by public clients"
+// @Success			200
+// @Router			/connect/revocation 	[post]
+func (h *Handler) Revocation(ctx *gin.Context) {
+	creds := clientCredentials(ctx.Request)
+	clientID := creds.ClientID
+	if err := h.svc.AuthenticateClient(ctx, creds); err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	token := ctx.PostForm("token")
+	if token == "" {
+		h.tokenError(ctx, errors.ErrInvalidRequest)
+		return
+	}
+	if err := h.revoke(ctx, clientID, token); err != nil {
+		h.tokenError(ctx, err)
+		return
+	}
+	// Unknown and already revoked tokens are not an error, see RFC 7009 section 2.2
+	ctx.Status(http.StatusOK)
+}
+
+// revoke removes an oauth2 token issued to the client or denylists a PASETO token.
+// A refresh token takes every token of its family with it.
+func (h *Handler) revoke(ctx *gin.Context, clientID, token string) error {
+	if ti, err := h.srv.Manager.LoadAccessToken(token); err == nil {
+		if ti.GetClientID() != clientID {
+			return nil
+		}
+		return h.srv.Manager.RemoveAccessToken(token)
+	}
+	if refresh, err := h.svc.DescribeRefreshToken(ctx, token); err == nil {
+		if refresh.ClientID != clientID {
+			return nil
+		}
+		return h.svc.RevokeRefreshToken(ctx, token)
+	}
+	payload, err := h.token.VerifyToken(token)
+	if err != nil {
+		return nil
+	}
+	return h.svc.RevokeToken(ctx, payload)
+}
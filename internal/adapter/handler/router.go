@@ -1,6 +1,7 @@
 package http
 
 import (
+	"crypto/tls"
 	"log/slog"
 	"net/http"
 	"os"
@@ -14,6 +15,9 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// apiBasePath is the path prefix of every route, the issuer url points at it
+const apiBasePath = "/api/v1/auth"
+
 // NewRouter creates a new HTTP router
 func (h *Handler) NewRouter() error {
 	// Disable debug mode in production
@@ -33,38 +37,64 @@ func (h *Handler) NewRouter() error {
 	}
 	// Swagger
 	h.router.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	v1 := h.router.Group("api/v1/auth")
+	v1 := h.router.Group(apiBasePath)
 	// Set Swagger
 	setupSwagger(v1)
-	v1.POST("/connect/token", func(c *gin.Context) {
-		err := h.srv.HandleTokenRequest(c.Writer, c.Request)
-		if err != nil {
-			ErrorResponse(c, http.StatusInternalServerError, err)
-			return
-		}
-	})
 
+	v1.GET("/.well-known/openid-configuration", h.Discovery)
+	v1.GET("/.well-known/jwks.json", h.JWKS)
+	h.Connect(v1)
 	h.User(v1)
 	h.Customer(v1)
 	h.Application(v1)
 	h.Client(v1)
 	h.Secret(v1)
+	h.Key(v1)
+	h.Resource(v1)
+	h.IdentityResource(v1)
 
 	return nil
 }
 
+// Connect Endpoint
+func (h *Handler) Connect(v1 *gin.RouterGroup) {
+	connect := v1.Group("/connect")
+	{
+		connect.GET("/authorize", h.Authorize)
+		connect.POST("/authorize", h.Authorize)
+		connect.POST("/token", h.Token)
+		connect.POST("/par", h.PushedAuthorization)
+		connect.POST("/register", authMiddleware(h.token, h.svc), h.RegisterClient)
+		connect.GET("/register/:client_id", h.GetClientRegistration)
+		connect.PUT("/register/:client_id", h.UpdateClientRegistration)
+		connect.DELETE("/register/:client_id", h.DeleteClientRegistration)
+		connect.POST("/deviceauthorization", h.DeviceAuthorization)
+		connect.GET("/device", h.DeviceVerification)
+		connect.POST("/device", h.DeviceVerification)
+		connect.POST("/introspect", h.Introspect)
+		connect.POST("/revocation", h.Revocation)
+		connect.GET("/endsession", h.EndSession)
+		connect.POST("/endsession", h.EndSession)
+		connect.GET("/userinfo", accessTokenMiddleware(h.srv, h.svc, h.config.Issuer), h.UserInfo)
+		connect.POST("/userinfo", accessTokenMiddleware(h.srv, h.svc, h.config.Issuer), h.UserInfo)
+	}
+}
+
 func (h *Handler) User(v1 *gin.RouterGroup) {
 	user := v1.Group("/users")
 	{
 		user.POST("/register", h.Register)
 		user.POST("/login", h.Login)
+		user.POST("/logout", authMiddleware(h.token, h.svc), h.Logout)
 
-		authUser := user.Group("/").Use(authMiddleware(h.token))
+		authUser := user.Group("/").Use(authMiddleware(h.token, h.svc))
 		{
+			authUser.GET("/consents", h.ListConsents)
+			authUser.DELETE("/consents/:client_id", h.WithdrawConsent)
 			authUser.GET("/", h.ListUsers)
 			authUser.GET("/:id", h.GetUser)
 
-			admin := authUser.Use(adminMiddleware())
+			admin := authUser.Use(adminMiddleware(h.svc))
 			{
 				admin.PUT("/:id", h.UpdateUser)
 				admin.DELETE("/:id", h.DeleteUser)
@@ -98,7 +128,7 @@ func (h *Handler) Application(v1 *gin.RouterGroup) {
 
 // Client Endpoint
 func (h *Handler) Client(v1 *gin.RouterGroup) {
-	client := v1.Group("/client")
+	client := v1.Group("/client").Use(authMiddleware(h.token, h.svc), adminMiddleware(h.svc))
 	{
 		client.POST("", h.CreateClient)
 		client.GET("/:id", h.GetClient)
@@ -109,7 +139,7 @@ func (h *Handler) Client(v1 *gin.RouterGroup) {
 
 // Secret Endpoint
 func (h *Handler) Secret(v1 *gin.RouterGroup) {
-	secret := v1.Group("/client-secret")
+	secret := v1.Group("/client-secret").Use(authMiddleware(h.token, h.svc), adminMiddleware(h.svc))
 	{
 		secret.POST("", h.CreateClientSecret)
 		secret.GET("/:id", h.GetClientSecret)
@@ -118,14 +148,69 @@ func (h *Handler) Secret(v1 *gin.RouterGroup) {
 	}
 }
 
-// Serve starts the HTTP server
+// Key Endpoint
+func (h *Handler) Key(v1 *gin.RouterGroup) {
+	key := v1.Group("/keys").Use(authMiddleware(h.token, h.svc), adminMiddleware(h.svc))
+	{
+		key.GET("", h.ListKeys)
+		key.POST("/rotate", h.RotateKeys)
+	}
+}
+
+// Resource Endpoint
+func (h *Handler) Resource(v1 *gin.RouterGroup) {
+	resource := v1.Group("/resources").Use(authMiddleware(h.token, h.svc), adminMiddleware(h.svc))
+	{
+		resource.POST("", h.CreateResource)
+		resource.GET("", h.ListResource)
+		resource.GET("/:id", h.GetResource)
+		resource.PUT("/:id", h.UpdateResource)
+		resource.DELETE("/:id", h.DeleteResource)
+		resource.POST("/:id/scopes", h.CreateApiScope)
+		resource.GET("/:id/scopes", h.ListApiScope)
+		resource.GET("/:id/scopes/:scope_id", h.GetApiScope)
+		resource.PUT("/:id/scopes/:scope_id", h.UpdateApiScope)
+		resource.DELETE("/:id/scopes/:scope_id", h.DeleteApiScope)
+		resource.POST("/:id/secrets", h.CreateApiSecret)
+		resource.GET("/:id/secrets", h.ListApiSecret)
+		resource.PUT("/:id/secrets/:secret_id", h.UpdateApiSecret)
+		resource.DELETE("/:id/secrets/:secret_id", h.DeleteApiSecret)
+	}
+}
+
+// IdentityResource Endpoint
+func (h *Handler) IdentityResource(v1 *gin.RouterGroup) {
+	identity := v1.Group("/identity-resources").Use(authMiddleware(h.token, h.svc), adminMiddleware(h.svc))
+	{
+		identity.POST("", h.CreateIdentityResource)
+		identity.GET("", h.ListIdentityResource)
+		identity.GET("/:id", h.GetIdentityResource)
+		identity.PUT("/:id", h.UpdateIdentityResource)
+		identity.DELETE("/:id", h.DeleteIdentityResource)
+	}
+}
+
+// Serve starts the HTTP server, over TLS when a certificate is configured. Client
+// certificates are requested but verified per client, so self-signed ones reach the
+// mutual TLS client authentication.
 func (h *Handler) Serve(listenAddr string) error {
 	err := h.NewRouter()
 	if err != nil {
 		slog.Error("Error initializing router", "error", err)
 		os.Exit(1)
 	}
-	return h.router.Run(listenAddr)
+	if h.config.TLSCertFile == "" {
+		return h.router.Run(listenAddr)
+	}
+	server := &http.Server{
+		Addr:    listenAddr,
+		Handler: h.router,
+		TLSConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ClientAuth: tls.RequestClientCert,
+		},
+	}
+	return server.ListenAndServeTLS(h.config.TLSCertFile, h.config.TLSKeyFile)
 }
 
 // Swagger host path and basepath configuration
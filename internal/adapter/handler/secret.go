@@ -10,7 +10,7 @@ import (
 
 // AddClientSecret	godoc
 // @Summary			Add a new ClientSecret
-// @Description		Add a new ClientSecret
+// @Description		Add a new ClientSecret. Shared secrets are always generated, signing secrets when the value is omitted, and are returned only in this response
 // @Tags			ClientSecret
 // @Accept			json
 // @Produce			json
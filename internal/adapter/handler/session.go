@@ -0,0 +1,111 @@
+package http
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
+)
+
+// sessionCookieName is the cookie holding the sign in session shared by the clients of a browser
+const sessionCookieName = "authserver.session"
+
+// csrfCookieName is the cookie holding the token the sign in, consent and device forms post back
+const csrfCookieName = "authserver.csrf"
+
+// csrfFormField is the hidden form field carrying the csrf token
+const csrfFormField = "csrf_token"
+
+// EndSession 		godoc
+// @Summary			End session endpoint
+// @Description		Sign the user out of the session and of every client that took part in it, then redirect to a registered post_logout_redirect_uri
+// @Tags			Connect
+// @Accept			x-www-form-urlencoded
+// @Produce			html
+// @Param			id_token_hint				query		string		false		"id_token previously issued to the client"
+// @Param			client_id					query		string		false		"Client id, required with post_logout_redirect_uri when id_token_hint is omitted"
+// @Param			post_logout_redirect_uri	query		string		false		"Registered post logout redirect uri"
+// @Param			state						query		string		false		"Opaque client state"
+// @Success			200
+// @Success			302
+// @Router			/connect/endsession 	[get]
+func (h *Handler) EndSession(ctx *gin.Context) {
+	var req domain.EndSessionRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	sessionID, _ := ctx.Cookie(sessionCookieName)
+	result, err := h.svc.EndSession(ctx, sessionID, &req)
+	if err != nil {
+		ErrorResponse(ctx, http.StatusBadRequest, err)
+		return
+	}
+	setSessionCookie(ctx, "")
+	if len(result.FrontChannelLogoutUris) == 0 && result.RedirectURI != "" {
+		ctx.Redirect(http.StatusFound, result.RedirectURI)
+		return
+	}
+	HTMLResponse(ctx, http.StatusOK, logoutTemplate, result)
+}
+
+// userSession returns the unexpired sign in session of the browser, or nil
+func (h *Handler) userSession(ctx *gin.Context) *domain.UserSession {
+	sessionID, err := ctx.Cookie(sessionCookieName)
+	if err != nil || sessionID == "" {
+		return nil
+	}
+	session, err := h.svc.GetUserSession(ctx, sessionID)
+	if err != nil {
+		return nil
+	}
+	return session
+}
+
+// setSessionCookie stores the session id in the browser, an empty id removes the cookie
+func setSessionCookie(ctx *gin.Context, sessionID string) {
+	maxAge := 0
+	if sessionID == "" {
+		maxAge = -1
+	}
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    sessionID,
+		Path:     apiBasePath,
+		MaxAge:   maxAge,
+		Secure:   ctx.Request.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// csrfToken returns the csrf token of the browser for a rendered form, creating the cookie when missing
+func csrfToken(ctx *gin.Context) string {
+	if token, err := ctx.Cookie(csrfCookieName); err == nil && token != "" {
+		return token
+	}
+	token, err := util.RandomToken(32)
+	if err != nil {
+		return ""
+	}
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     csrfCookieName,
+		Value:    token,
+		Path:     apiBasePath,
+		Secure:   ctx.Request.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return token
+}
+
+// validCSRFToken reports whether a posted form carries the csrf token of the browser cookie
+func validCSRFToken(ctx *gin.Context) bool {
+	token, err := ctx.Cookie(csrfCookieName)
+	if err != nil || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(ctx.PostForm(csrfFormField))) == 1
+}
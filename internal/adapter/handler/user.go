@@ -61,6 +61,23 @@ func (uh *Handler) Login(ctx *gin.Context) {
 	})
 }
 
+// Logout 		godoc
+// @Summary			Logout a user
+// @Description		Revoke the access token of the signed in user
+// @Tags			Users
+// @Produce			json
+// @Success			200
+// @Router			/users/logout [post]
+// @Security		BearerAuth
+func (uh *Handler) Logout(ctx *gin.Context) {
+	payload := getAuthPayload(ctx, authorizationPayloadKey)
+	if err := uh.svc.RevokeToken(ctx, payload); err != nil {
+		ErrorResponse(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	SuccessResponse(ctx, nil)
+}
+
 // listUsersRequest represents the request body for listing users
 type listUsersRequest struct {
 	Skip  uint64 `form:"skip" example:"0"`
@@ -1,21 +1,169 @@
 package middleware
 
 import (
+	"container/heap"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
-	"gopkg.in/oauth2.v3/server"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
 )
 
-var jwtSecret = []byte("supersecretkey")
-
 type JWTClaims struct {
-	ClientID string `json:"client_id"`
-	Scope    string `json:"scope"`
+	ClientID string               `json:"client_id"`
+	Scope    string               `json:"scope"`
+	Audience domain.Audience      `json:"aud,omitempty"`
+	Cnf      *domain.Confirmation `json:"cnf,omitempty"`
 	jwt.StandardClaims
 }
 
-func ValidateToken(f http.HandlerFunc, srv *server.Server) http.HandlerFunc {
+// replayCache remembers the DPoP proofs seen, by key thumbprint and jti, until they are too old
+// to be accepted. A min-heap on the expiration lets each call evict only the expired entries.
+type replayCache struct {
+	mu    sync.Mutex
+	seen  map[string]time.Time
+	queue replayQueue
+}
+
+// replayEntry is a proof in the eviction queue of the replay cache
+type replayEntry struct {
+	key        string
+	expiration time.Time
+}
+
+// replayQueue implements heap.Interface ordered by expiration
+type replayQueue []replayEntry
+
+func (q replayQueue) Len() int            { return len(q) }
+func (q replayQueue) Less(i, j int) bool  { return q[i].expiration.Before(q[j].expiration) }
+func (q replayQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *replayQueue) Push(x interface{}) { *q = append(*q, x.(replayEntry)) }
+func (q *replayQueue) Pop() interface{} {
+	old := *q
+	entry := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return entry
+}
+
+// dpopReplays is shared by every handler wrapped by ValidateToken
+var dpopReplays = newReplayCache()
+
+func newReplayCache() *replayCache {
+	return &replayCache{seen: map[string]time.Time{}}
+}
+
+// add records the proof and reports false when a proof of the same key with the same jti was already used
+func (c *replayCache) add(jkt, jti string, expiration time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for c.queue.Len() > 0 && c.queue[0].expiration.Before(now) {
+		entry := heap.Pop(&c.queue).(replayEntry)
+		delete(c.seen, entry.key)
+	}
+	key := jkt + ":" + jti
+	if _, ok := c.seen[key]; ok {
+		return false
+	}
+	c.seen[key] = expiration
+	heap.Push(&c.queue, replayEntry{key: key, expiration: expiration})
+	return true
+}
+
+// JWKS fetches and caches the public keys published by the auth server
+type JWKS struct {
+	url     string
+	issuer  string
+	client  *http.Client
+	mu      sync.RWMutex
+	keys    map[string]jwkKey
+	fetched time.Time
+}
+
+type jwkKey struct {
+	alg string
+	key interface{}
+}
+
+// minRefreshInterval limits how often an unknown kid triggers a refetch
+const minRefreshInterval = time.Minute
+
+// NewJWKS creates a key set backed by the jwks_uri of the auth server, accepting only tokens
+// whose iss is the issuer of that auth server
+func NewJWKS(url, issuer string) *JWKS {
+	return &JWKS{
+		url:    url,
+		issuer: issuer,
+		client: &http.Client{Timeout: 10 * time.Second},
+		keys:   map[string]jwkKey{},
+	}
+}
+
+// Keyfunc resolves the verification key from the kid header of a token
+func (j *JWKS) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("token has no kid header")
+	}
+	key, ok := j.lookup(kid)
+	if !ok {
+		if err := j.refresh(); err != nil {
+			return nil, err
+		}
+		key, ok = j.lookup(kid)
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+	}
+	if token.Method.Alg() != key.alg {
+		return nil, errors.New("unexpected signing algorithm")
+	}
+	return key.key, nil
+}
+
+func (j *JWKS) lookup(kid string) (jwkKey, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	key, ok := j.keys[kid]
+	return key, ok
+}
+
+func (j *JWKS) refresh() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if time.Since(j.fetched) < minRefreshInterval {
+		return nil
+	}
+	j.fetched = time.Now()
+	resp, err := j.client.Get(j.url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var set domain.JSONWebKeySet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return err
+	}
+	keys := map[string]jwkKey{}
+	for _, jwk := range set.Keys {
+		key, err := util.ParseJSONWebKey(jwk)
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = jwkKey{alg: jwk.Alg, key: key}
+	}
+	j.keys = keys
+	return nil
+}
+
+// ValidateToken accepts at+jwt access tokens signed and issued by the auth server for the audience
+// of the resource server, see RFC 9068 section 4
+func ValidateToken(f http.HandlerFunc, jwks *JWKS, audience string) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		tokenStr := r.Header.Get("Authorization")
 		if tokenStr == "" {
@@ -23,16 +171,69 @@ func ValidateToken(f http.HandlerFunc, srv *server.Server) http.HandlerFunc {
 			return
 		}
 
-		tokenStr = tokenStr[len("Bearer "):]
-		token, err := jwt.ParseWithClaims(tokenStr, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-			return jwtSecret, nil
-		})
+		scheme, tokenStr, _ := strings.Cut(tokenStr, " ")
+		if !strings.EqualFold(scheme, "Bearer") && !strings.EqualFold(scheme, domain.TokenTypeDPoP) {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		claims := &JWTClaims{}
+		token, err := jwt.ParseWithClaims(tokenStr, claims, jwks.Keyfunc)
 
 		if err != nil || !token.Valid {
 			http.Error(w, "invalid token", http.StatusUnauthorized)
 			return
 		}
+		if typ, _ := token.Header["typ"].(string); !strings.EqualFold(typ, domain.AccessTokenType) && !strings.EqualFold(typ, "application/"+domain.AccessTokenType) {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		if jwks.issuer == "" || claims.Issuer != jwks.issuer || audience == "" || !claims.Audience.Contains(audience) {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		// A token bound to a client certificate is only accepted over a connection presenting it, see RFC 8705 section 3
+		if claims.Cnf != nil && claims.Cnf.X5tS256 != "" {
+			if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 || util.CertificateThumbprint(r.TLS.PeerCertificates[0]) != claims.Cnf.X5tS256 {
+				http.Error(w, "invalid token", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		// A token bound to a DPoP key is only accepted with the DPoP scheme and a fresh proof of that key
+		bound := claims.Cnf != nil && claims.Cnf.JKT != ""
+		if bound || strings.EqualFold(scheme, domain.TokenTypeDPoP) {
+			if !bound || !strings.EqualFold(scheme, domain.TokenTypeDPoP) {
+				http.Error(w, "invalid token", http.StatusUnauthorized)
+				return
+			}
+			if err := validateDPoPProof(r, tokenStr, claims.Cnf.JKT); err != nil {
+				http.Error(w, "invalid dpop proof", http.StatusUnauthorized)
+				return
+			}
+		}
 
 		f.ServeHTTP(w, r)
 	})
 }
+
+// validateDPoPProof checks the single DPoP proof of the request against the access token and the
+// thumbprint of the key it is bound to
+func validateDPoPProof(r *http.Request, accessToken, jkt string) error {
+	proofs := r.Header.Values(domain.DPoPHeader)
+	if len(proofs) != 1 {
+		return util.ErrInvalidDPoPProof
+	}
+	scheme := "https"
+	if r.TLS == nil {
+		scheme = "http"
+	}
+	proof, err := util.VerifyDPoPProof(proofs[0], r.Method, scheme+"://"+r.Host+r.URL.Path, accessToken)
+	if err != nil {
+		return err
+	}
+	if proof.JKT != jkt || !dpopReplays.add(proof.JKT, proof.JTI, proof.IssuedAt.Add(util.DPoPProofLifetime)) {
+		return util.ErrInvalidDPoPProof
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReplayCache(t *testing.T) {
+	cache := newReplayCache()
+	exp := time.Now().Add(time.Minute)
+	if !cache.add("jkt1", "jti", exp) {
+		t.Fatal("first proof rejected")
+	}
+	if cache.add("jkt1", "jti", exp) {
+		t.Fatal("replayed proof accepted")
+	}
+	if !cache.add("jkt2", "jti", exp) {
+		t.Fatal("proof of another key with the same jti rejected")
+	}
+}
+
+func TestReplayCacheEvictsExpired(t *testing.T) {
+	cache := newReplayCache()
+	cache.add("jkt", "old", time.Now().Add(-time.Second))
+	cache.add("jkt", "new", time.Now().Add(time.Minute))
+	cache.add("jkt", "other", time.Now().Add(time.Minute))
+	if _, ok := cache.seen["jkt:old"]; ok {
+		t.Fatal("expired proof not evicted")
+	}
+	if len(cache.seen) != 2 || cache.queue.Len() != 2 {
+		t.Fatalf("seen %d queued %d, want 2", len(cache.seen), cache.queue.Len())
+	}
+}
@@ -5,12 +5,42 @@ import (
 	"github.com/sugaml/authserver/internal/core/domain"
 )
 
-// Migrate up database table
+// Migrate up database table, hash plain text secrets and seed the admin role and standard identity resources
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	err := db.AutoMigrate(
 		&domain.User{},
+		&domain.UserClaim{},
+		&domain.UserRole{},
+		&domain.Role{},
 		&domain.Customer{},
 		&domain.Client{},
 		&domain.ClientSecret{},
+		&domain.ClientCorsOrigin{},
+		&domain.ClientGrantType{},
+		&domain.ClientProperty{},
+		&domain.ClientRedirectUri{},
+		&domain.ClientScope{},
+		&domain.PersistedGrant{},
+		&domain.AuditMessage{},
+		&domain.DeviceCode{},
+		&domain.IdentityResource{},
+		&domain.IdentityClaim{},
+		&domain.IdentityProperty{},
+		&domain.Resource{},
+		&domain.ApiScope{},
+		&domain.ApiScopeClaim{},
+		&domain.ApiSecret{},
+		&domain.ApiProperty{},
+		&domain.Key{},
 	).Error
+	if err != nil {
+		return err
+	}
+	if err := hashLegacySecrets(db); err != nil {
+		return err
+	}
+	if err := seedAdminRole(db); err != nil {
+		return err
+	}
+	return seedIdentityResources(db)
 }
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
+)
+
+// hashLegacySecrets replaces shared secrets stored as plain text before secrets were hashed,
+// so they keep working while only hashes are accepted
+func hashLegacySecrets(db *gorm.DB) error {
+	var clientSecrets []*domain.ClientSecret
+	err := db.Where("(type = '' OR type = ?) AND value NOT LIKE ?", domain.SecretTypeSharedSecret, "sha256$%").
+		Find(&clientSecrets).Error
+	if err != nil {
+		return err
+	}
+	var apiSecrets []*domain.ApiSecret
+	if err := db.Where("value NOT LIKE ?", "sha256$%").Find(&apiSecrets).Error; err != nil {
+		return err
+	}
+	tx := db.Begin()
+	for _, secret := range clientSecrets {
+		if err := updateSecretHash(tx, &domain.ClientSecret{}, secret.ID, secret.Value); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	for _, secret := range apiSecrets {
+		if err := updateSecretHash(tx, &domain.ApiSecret{}, secret.ID, secret.Value); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+// updateSecretHash stores the hash of a plain text secret value
+func updateSecretHash(tx *gorm.DB, model interface{}, id, value string) error {
+	hash, err := util.HashSecret(value)
+	if err != nil {
+		return err
+	}
+	return tx.Model(model).Where("id = ?", id).UpdateColumn("value", hash).Error
+}
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+// seedIdentityResources creates the standard OpenID Connect identity resources when the table is
+// empty, so a new database can issue id_tokens right away. Existing resources are never changed.
+func seedIdentityResources(db *gorm.DB) error {
+	var count int
+	if err := db.Model(&domain.IdentityResource{}).Unscoped().Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	tx := db.Begin()
+	for _, req := range domain.StandardIdentityResources() {
+		data := &domain.IdentityResource{}
+		data.New(req)
+		if err := tx.Create(data).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+// seedAdminRole creates the role adminMiddleware requires for the key, resource and identity
+// resource routes, so it only has to be granted to a user
+func seedAdminRole(db *gorm.DB) error {
+	var count int
+	if err := db.Model(&domain.Role{}).Where("normalized_name = ?", domain.RoleAdmin).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return db.Create(&domain.Role{Name: "Admin", NormalizedName: domain.RoleAdmin}).Error
+}
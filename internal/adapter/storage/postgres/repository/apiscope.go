@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jinzhu/gorm"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/port"
+)
+
+type ApiScopeGetter interface {
+	ApiScope() port.ApiScopeRepository
+}
+
+type ApiScopeRepository struct {
+	db *gorm.DB
+}
+
+func newApiScopeRepository(db *gorm.DB) *ApiScopeRepository {
+	return &ApiScopeRepository{
+		db: db,
+	}
+}
+
+func (r *ApiScopeRepository) ListDiscoverable(ctx context.Context) ([]*domain.ApiScope, error) {
+	var datas []*domain.ApiScope
+	err := r.db.Model(&domain.ApiScope{}).
+		Joins("JOIN resources ON resources.id = api_scopes.api_resource_id").
+		Where("api_scopes.show_in_discovery_document = ? AND resources.enabled = ? AND resources.deleted_at IS NULL", true, true).
+		Order("api_scopes.name").
+		Find(&datas).Error
+	if err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *ApiScopeRepository) ListByNames(ctx context.Context, names []string) ([]*domain.ApiScope, error) {
+	var datas []*domain.ApiScope
+	err := r.db.Model(&domain.ApiScope{}).
+		Where("name IN (?)", names).
+		Find(&datas).Error
+	if err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *ApiScopeRepository) ListResourceNames(ctx context.Context, scopes []string) ([]string, error) {
+	var names []string
+	err := r.db.Model(&domain.ApiScope{}).
+		Joins("JOIN resources ON resources.id = api_scopes.api_resource_id").
+		Where("api_scopes.name IN (?) AND resources.enabled = ? AND resources.deleted_at IS NULL", scopes, true).
+		Pluck("DISTINCT resources.name", &names).Error
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+func (r *ApiScopeRepository) Create(ctx context.Context, data *domain.ApiScope) (*domain.ApiScope, error) {
+	if err := r.db.Model(&domain.ApiScope{}).Create(data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *ApiScopeRepository) ListByApiResourceID(ctx context.Context, id string) ([]*domain.ApiScope, error) {
+	var datas []*domain.ApiScope
+	err := r.db.Model(&domain.ApiScope{}).
+		Preload("ApiScopeClaims").
+		Where("api_resource_id = ?", id).
+		Order("name").
+		Find(&datas).Error
+	if err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+// ListEnabledByNames returns the scopes among names whose api resource is enabled
+func (r *ApiScopeRepository) ListEnabledByNames(ctx context.Context, names []string) ([]*domain.ApiScope, error) {
+	var datas []*domain.ApiScope
+	err := r.db.Model(&domain.ApiScope{}).
+		Joins("JOIN resources ON resources.id = api_scopes.api_resource_id").
+		Where("api_scopes.name IN (?) AND resources.enabled = ? AND resources.deleted_at IS NULL", names, true).
+		Find(&datas).Error
+	if err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *ApiScopeRepository) Get(ctx context.Context, id string) (*domain.ApiScope, error) {
+	var data domain.ApiScope
+	if err := r.db.Model(&domain.ApiScope{}).
+		Preload("ApiScopeClaims").
+		Take(&data, "id = ?", id).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Update saves the scope and replaces its claims
+func (r *ApiScopeRepository) Update(ctx context.Context, data *domain.ApiScope) (*domain.ApiScope, error) {
+	tx := r.db.Begin()
+	if err := tx.Where("api_scope_id = ?", data.ID).Delete(&domain.ApiScopeClaim{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	// Save would also store the claims, which are recreated below
+	claims := data.ApiScopeClaims
+	data.ApiScopeClaims = nil
+	if err := tx.Save(data).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	for i := range claims {
+		claims[i].ApiScopeID = data.ID
+		if err := tx.Create(&claims[i]).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	data.ApiScopeClaims = claims
+	return data, nil
+}
+
+func (r *ApiScopeRepository) Delete(ctx context.Context, id string) error {
+	tx := r.db.Begin()
+	if err := tx.Where("api_scope_id = ?", id).Delete(&domain.ApiScopeClaim{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("id = ?", id).Delete(&domain.ApiScope{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jinzhu/gorm"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/port"
+)
+
+type ApiSecretGetter interface {
+	ApiSecret() port.ApiSecretRepository
+}
+
+type ApiSecretRepository struct {
+	db *gorm.DB
+}
+
+func newApiSecretRepository(db *gorm.DB) *ApiSecretRepository {
+	return &ApiSecretRepository{
+		db: db,
+	}
+}
+
+func (r *ApiSecretRepository) ListByApiResourceID(ctx context.Context, id string) ([]*domain.ApiSecret, error) {
+	var datas []*domain.ApiSecret
+	err := r.db.Model(&domain.ApiSecret{}).Where("api_resource_id = ?", id).Find(&datas).Error
+	if err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *ApiSecretRepository) Create(ctx context.Context, data *domain.ApiSecret) (*domain.ApiSecret, error) {
+	if err := r.db.Model(&domain.ApiSecret{}).Create(data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *ApiSecretRepository) Get(ctx context.Context, id string) (*domain.ApiSecret, error) {
+	var data domain.ApiSecret
+	if err := r.db.Model(&domain.ApiSecret{}).
+		Take(&data, "id = ?", id).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *ApiSecretRepository) Update(ctx context.Context, id string, req domain.Map) (*domain.ApiSecret, error) {
+	data := &domain.ApiSecret{}
+	err := r.db.Model(&domain.ApiSecret{}).Where("id = ?", id).Updates(req).Take(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *ApiSecretRepository) Delete(ctx context.Context, id string) error {
+	return r.db.Model(&domain.ApiSecret{}).Where("id = ?", id).Delete(&domain.ApiSecret{}).Error
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jinzhu/gorm"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/port"
+)
+
+type AuditMessageGetter interface {
+	AuditMessage() port.AuditMessageRepository
+}
+
+type AuditMessageRepository struct {
+	db *gorm.DB
+}
+
+func newAuditMessageRepository(db *gorm.DB) *AuditMessageRepository {
+	return &AuditMessageRepository{
+		db: db,
+	}
+}
+
+func (r *AuditMessageRepository) Create(ctx context.Context, data *domain.AuditMessage) (*domain.AuditMessage, error) {
+	if err := r.db.Model(&domain.AuditMessage{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"github.com/jinzhu/gorm"
 	"github.com/sugaml/authserver/internal/core/domain"
 	"github.com/sugaml/authserver/internal/core/port"
@@ -81,6 +82,22 @@ func (r *ClientRepository) GetCliendID(ctx context.Context, clientID string) (*d
 	return &data, nil
 }
 
+func (r *ClientRepository) GetDetailByClientID(ctx context.Context, clientID string) (*domain.Client, error) {
+	var data domain.Client
+	err := r.db.Model(&domain.Client{}).
+		Preload("ClientCorsOrigins").
+		Preload("ClientGrantTypes").
+		Preload("ClientProperties").
+		Preload("ClientRedirectUris").
+		Preload("ClientScopes").
+		Preload("ClientSecrets").
+		Take(&data, "client_id = ?", clientID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
 func (r *ClientRepository) Update(ctx context.Context, id string, req domain.Map) (*domain.Client, error) {
 	data := &domain.Client{}
 	err := r.db.Model(&domain.Client{}).Where("id = ?", id).Updates(req).Take(&data).Error
@@ -105,6 +122,108 @@ func (r *ClientRepository) UpdateIsActive(ctx context.Context, id string, isActi
 	return data, nil
 }
 
+// Delete deletes the client together with its uris, grant types, scopes, properties and secrets in one transaction
 func (r *ClientRepository) Delete(ctx context.Context, id string) error {
-	return r.db.Model(&domain.Client{}).Where("id = ?", id).Delete(&domain.Client{}).Error
+	tx := r.db.Begin()
+	steps := []func() error{
+		func() error { return tx.Where("client_id = ?", id).Delete(&domain.ClientCorsOrigin{}).Error },
+		func() error { return tx.Where("client_id = ?", id).Delete(&domain.ClientRedirectUri{}).Error },
+		func() error { return tx.Where("client_id = ?", id).Delete(&domain.ClientGrantType{}).Error },
+		func() error { return tx.Where("client_id = ?", id).Delete(&domain.ClientScope{}).Error },
+		func() error { return tx.Where("client_id = ?", id).Delete(&domain.ClientProperty{}).Error },
+		func() error { return tx.Where("client_id = ?", id).Delete(&domain.ClientSecret{}).Error },
+		func() error { return tx.Where("id = ?", id).Delete(&domain.Client{}).Error },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+// UpdateProperties replaces the client properties of the given keys in one transaction,
+// an empty value removes the property
+func (r *ClientRepository) UpdateProperties(ctx context.Context, id string, props map[string]string) error {
+	tx := r.db.Begin()
+	for key, value := range props {
+		err := tx.Where("client_id = ? AND key = ?", id, key).Delete(&domain.ClientProperty{}).Error
+		if err == nil && value != "" {
+			err = tx.Create(&domain.ClientProperty{ID: uuid.New().String(), Key: key, Value: value, ClientID: id}).Error
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+// CreateRegistration inserts a dynamically registered client with its metadata and secret
+// together with its registration access token in one transaction
+func (r *ClientRepository) CreateRegistration(ctx context.Context, data *domain.Client, token *domain.PersistedGrant) (*domain.Client, error) {
+	tx := r.db.Begin()
+	if err := tx.Create(data).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Create(token).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// registrationPropertyKeys are the client properties set from dynamic registration metadata
+var registrationPropertyKeys = []string{
+	domain.ClientPropertyTokenEndpointAuthMethod,
+	domain.ClientPropertyPostLogoutRedirectUris,
+	domain.ClientPropertyBackChannelLogoutUri,
+}
+
+// UpdateRegistration replaces the name, uri, redirect uris, grant types, scopes, token endpoint
+// authentication method and logout uris of a dynamically registered client in one transaction
+func (r *ClientRepository) UpdateRegistration(ctx context.Context, data *domain.Client) (*domain.Client, error) {
+	tx := r.db.Begin()
+	err := tx.Model(&domain.Client{}).Where("id = ?", data.ID).
+		Updates(map[string]interface{}{
+			"client_name": data.ClientName,
+			"client_uri":  data.ClientUri,
+		}).Error
+	if err == nil {
+		err = tx.Where("client_id = ?", data.ID).Delete(&domain.ClientRedirectUri{}).Error
+	}
+	if err == nil {
+		err = tx.Where("client_id = ?", data.ID).Delete(&domain.ClientGrantType{}).Error
+	}
+	if err == nil {
+		err = tx.Where("client_id = ?", data.ID).Delete(&domain.ClientScope{}).Error
+	}
+	if err == nil {
+		err = tx.Where("client_id = ? AND key IN (?)", data.ID, registrationPropertyKeys).Delete(&domain.ClientProperty{}).Error
+	}
+	for i := 0; err == nil && i < len(data.ClientRedirectUris); i++ {
+		err = tx.Create(&data.ClientRedirectUris[i]).Error
+	}
+	for i := 0; err == nil && i < len(data.ClientGrantTypes); i++ {
+		err = tx.Create(&data.ClientGrantTypes[i]).Error
+	}
+	for i := 0; err == nil && i < len(data.ClientScopes); i++ {
+		err = tx.Create(&data.ClientScopes[i]).Error
+	}
+	for i := 0; err == nil && i < len(data.ClientProperties); i++ {
+		err = tx.Create(&data.ClientProperties[i]).Error
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return r.GetDetailByClientID(ctx, data.ClientID)
 }
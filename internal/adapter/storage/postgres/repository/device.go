@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jinzhu/gorm"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/port"
+)
+
+type DeviceCodeGetter interface {
+	DeviceCode() port.DeviceCodeRepository
+}
+
+type DeviceCodeRepository struct {
+	db *gorm.DB
+}
+
+func newDeviceCodeRepository(db *gorm.DB) *DeviceCodeRepository {
+	return &DeviceCodeRepository{
+		db: db,
+	}
+}
+
+func (r *DeviceCodeRepository) Create(ctx context.Context, data *domain.DeviceCode) (*domain.DeviceCode, error) {
+	if err := r.db.Model(&domain.DeviceCode{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *DeviceCodeRepository) GetByUserCode(ctx context.Context, userCode string) (*domain.DeviceCode, error) {
+	var data domain.DeviceCode
+	if err := r.db.Model(&domain.DeviceCode{}).
+		Take(&data, "user_code = ?", userCode).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *DeviceCodeRepository) GetByDeviceCode(ctx context.Context, deviceCode string) (*domain.DeviceCode, error) {
+	var data domain.DeviceCode
+	if err := r.db.Model(&domain.DeviceCode{}).
+		Take(&data, "device_code = ?", deviceCode).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *DeviceCodeRepository) Update(ctx context.Context, userCode string, req domain.Map) error {
+	return r.db.Model(&domain.DeviceCode{}).Where("user_code = ?", userCode).Updates(req).Error
+}
+
+// Redeem deletes an approved device code, returning ErrDataNotFound when a concurrent poll already redeemed it
+func (r *DeviceCodeRepository) Redeem(ctx context.Context, userCode string) error {
+	result := r.db.Model(&domain.DeviceCode{}).
+		Where("user_code = ? AND subject_id <> ''", userCode).
+		Delete(&domain.DeviceCode{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrDataNotFound
+	}
+	return nil
+}
+
+func (r *DeviceCodeRepository) Delete(ctx context.Context, userCode string) error {
+	return r.db.Model(&domain.DeviceCode{}).Where("user_code = ?", userCode).Delete(&domain.DeviceCode{}).Error
+}
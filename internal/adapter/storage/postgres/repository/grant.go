@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/port"
+)
+
+type PersistedGrantGetter interface {
+	PersistedGrant() port.PersistedGrantRepository
+}
+
+type PersistedGrantRepository struct {
+	db *gorm.DB
+}
+
+func newPersistedGrantRepository(db *gorm.DB) *PersistedGrantRepository {
+	return &PersistedGrantRepository{
+		db: db,
+	}
+}
+
+func (r *PersistedGrantRepository) Create(ctx context.Context, data *domain.PersistedGrant) (*domain.PersistedGrant, error) {
+	if err := r.db.Model(&domain.PersistedGrant{}).Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *PersistedGrantRepository) Get(ctx context.Context, key string) (*domain.PersistedGrant, error) {
+	var data domain.PersistedGrant
+	if err := r.db.Model(&domain.PersistedGrant{}).
+		Take(&data, "key = ?", key).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *PersistedGrantRepository) Delete(ctx context.Context, key string) error {
+	return r.db.Model(&domain.PersistedGrant{}).Where("key = ?", key).Delete(&domain.PersistedGrant{}).Error
+}
+
+// Redeem deletes a single use grant, returning ErrDataNotFound when a concurrent request already deleted it
+func (r *PersistedGrantRepository) Redeem(ctx context.Context, key string) error {
+	result := r.db.Model(&domain.PersistedGrant{}).Where("key = ?", key).Delete(&domain.PersistedGrant{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrDataNotFound
+	}
+	return nil
+}
+
+// Consume marks an unconsumed grant consumed, returning ErrDataNotFound when it was missing or already consumed
+func (r *PersistedGrantRepository) Consume(ctx context.Context, key string, consumed time.Time) error {
+	result := r.db.Model(&domain.PersistedGrant{}).
+		Where("key = ? AND consumed_time IS NULL", key).
+		UpdateColumn("consumed_time", consumed)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrDataNotFound
+	}
+	return nil
+}
+
+func (r *PersistedGrantRepository) ListBySubject(ctx context.Context, subjectID, grantType string) ([]*domain.PersistedGrant, error) {
+	var datas []*domain.PersistedGrant
+	err := r.db.Model(&domain.PersistedGrant{}).
+		Where("subject_id = ? AND type = ?", subjectID, grantType).
+		Order("creation_time").
+		Find(&datas).Error
+	if err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *PersistedGrantRepository) DeleteBySubject(ctx context.Context, subjectID, clientID string, grantTypes []string) error {
+	return r.db.Model(&domain.PersistedGrant{}).
+		Where("subject_id = ? AND client_id = ? AND type IN (?)", subjectID, clientID, grantTypes).
+		Delete(&domain.PersistedGrant{}).Error
+}
+
+func (r *PersistedGrantRepository) ListByFamily(ctx context.Context, familyID string) ([]*domain.PersistedGrant, error) {
+	var datas []*domain.PersistedGrant
+	if err := r.db.Model(&domain.PersistedGrant{}).Where("family_id = ?", familyID).Find(&datas).Error; err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *PersistedGrantRepository) DeleteByFamily(ctx context.Context, familyID string) error {
+	return r.db.Model(&domain.PersistedGrant{}).Where("family_id = ?", familyID).Delete(&domain.PersistedGrant{}).Error
+}
+
+// DeleteExpired removes grants that expired before the given time, a zero expiration never expires
+func (r *PersistedGrantRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.Model(&domain.PersistedGrant{}).
+		Where("expiration > ? AND expiration < ?", time.Time{}, before).
+		Delete(&domain.PersistedGrant{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jinzhu/gorm"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/port"
+)
+
+type IdentityResourceGetter interface {
+	IdentityResource() port.IdentityResourceRepository
+}
+
+type IdentityResourceRepository struct {
+	db *gorm.DB
+}
+
+func newIdentityResourceRepository(db *gorm.DB) *IdentityResourceRepository {
+	return &IdentityResourceRepository{
+		db: db,
+	}
+}
+
+func (r *IdentityResourceRepository) ListDiscoverable(ctx context.Context) ([]*domain.IdentityResource, error) {
+	var datas []*domain.IdentityResource
+	err := r.db.Model(&domain.IdentityResource{}).
+		Preload("IdentityClaims").
+		Where("enabled = ? AND show_in_discovery_document = ?", true, true).
+		Order("name").
+		Find(&datas).Error
+	if err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *IdentityResourceRepository) ListByNames(ctx context.Context, names []string) ([]*domain.IdentityResource, error) {
+	var datas []*domain.IdentityResource
+	err := r.db.Model(&domain.IdentityResource{}).
+		Preload("IdentityClaims").
+		Where("enabled = ? AND name IN (?)", true, names).
+		Find(&datas).Error
+	if err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *IdentityResourceRepository) Create(ctx context.Context, data *domain.IdentityResource) (*domain.IdentityResource, error) {
+	if err := r.db.Model(&domain.IdentityResource{}).Create(data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *IdentityResourceRepository) List(ctx context.Context) ([]*domain.IdentityResource, error) {
+	var datas []*domain.IdentityResource
+	err := r.db.Model(&domain.IdentityResource{}).
+		Preload("IdentityClaims").
+		Preload("IdentityProperties").
+		Order("name").
+		Find(&datas).Error
+	if err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *IdentityResourceRepository) Get(ctx context.Context, id string) (*domain.IdentityResource, error) {
+	var data domain.IdentityResource
+	if err := r.db.Model(&domain.IdentityResource{}).
+		Preload("IdentityClaims").
+		Preload("IdentityProperties").
+		Take(&data, "id = ?", id).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetByName returns the identity resource with the name, enabled or not
+func (r *IdentityResourceRepository) GetByName(ctx context.Context, name string) (*domain.IdentityResource, error) {
+	var data domain.IdentityResource
+	if err := r.db.Model(&domain.IdentityResource{}).
+		Take(&data, "name = ?", name).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Update saves the identity resource and replaces its claims and properties
+func (r *IdentityResourceRepository) Update(ctx context.Context, data *domain.IdentityResource) (*domain.IdentityResource, error) {
+	tx := r.db.Begin()
+	if err := tx.Where("identity_resource_id = ?", data.ID).Delete(&domain.IdentityClaim{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Where("identity_resource_id = ?", data.ID).Delete(&domain.IdentityProperty{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	// Save would also store the claims and properties, which are recreated below
+	claims, properties := data.IdentityClaims, data.IdentityProperties
+	data.IdentityClaims, data.IdentityProperties = nil, nil
+	if err := tx.Save(data).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	for i := range claims {
+		claims[i].IdentityResourceID = data.ID
+		if err := tx.Create(&claims[i]).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	for i := range properties {
+		properties[i].IdentityResourceID = data.ID
+		if err := tx.Create(&properties[i]).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	data.IdentityClaims, data.IdentityProperties = claims, properties
+	return data, nil
+}
+
+// Delete removes the identity resource together with its claims and properties
+func (r *IdentityResourceRepository) Delete(ctx context.Context, id string) error {
+	tx := r.db.Begin()
+	if err := tx.Where("identity_resource_id = ?", id).Delete(&domain.IdentityClaim{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("identity_resource_id = ?", id).Delete(&domain.IdentityProperty{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("id = ?", id).Delete(&domain.IdentityResource{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
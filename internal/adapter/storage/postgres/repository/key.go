@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/port"
+)
+
+// uniqueViolation is the postgres error code of a unique index conflict
+const uniqueViolation = "23505"
+
+type KeyGetter interface {
+	Key() port.KeyRepository
+}
+
+type KeyRepository struct {
+	db *gorm.DB
+}
+
+func newKeyRepository(db *gorm.DB) *KeyRepository {
+	return &KeyRepository{
+		db: db,
+	}
+}
+
+// Create inserts a key, returning ErrConflictingData when another instance already created its version
+func (r *KeyRepository) Create(ctx context.Context, data *domain.Key) (*domain.Key, error) {
+	if err := r.db.Model(&domain.Key{}).Create(&data).Error; err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolation {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// List returns the keys of a use ordered from the newest version
+func (r *KeyRepository) List(ctx context.Context, use string) ([]*domain.Key, error) {
+	var datas []*domain.Key
+	err := r.db.Model(&domain.Key{}).Where("use = ?", use).Order("version desc").Find(&datas).Error
+	if err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *KeyRepository) Delete(ctx context.Context, id string) error {
+	return r.db.Model(&domain.Key{}).Where("id = ?", id).Delete(&domain.Key{}).Error
+}
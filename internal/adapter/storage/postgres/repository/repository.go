@@ -18,6 +18,13 @@ type IRepository interface {
 	RoleGetter
 	ResourceGetter
 	TenantGetter
+	PersistedGrantGetter
+	AuditMessageGetter
+	DeviceCodeGetter
+	IdentityResourceGetter
+	ApiScopeGetter
+	ApiSecretGetter
+	KeyGetter
 }
 
 func NewRepository(db *gorm.DB) IRepository {
@@ -57,3 +64,31 @@ func (r *Repository) Resource() port.ResourceRepository {
 func (r *Repository) Role() port.RoleRepository {
 	return newRoleRepository(r.db)
 }
+
+func (r *Repository) PersistedGrant() port.PersistedGrantRepository {
+	return newPersistedGrantRepository(r.db)
+}
+
+func (r *Repository) AuditMessage() port.AuditMessageRepository {
+	return newAuditMessageRepository(r.db)
+}
+
+func (r *Repository) DeviceCode() port.DeviceCodeRepository {
+	return newDeviceCodeRepository(r.db)
+}
+
+func (r *Repository) IdentityResource() port.IdentityResourceRepository {
+	return newIdentityResourceRepository(r.db)
+}
+
+func (r *Repository) ApiScope() port.ApiScopeRepository {
+	return newApiScopeRepository(r.db)
+}
+
+func (r *Repository) ApiSecret() port.ApiSecretRepository {
+	return newApiSecretRepository(r.db)
+}
+
+func (r *Repository) Key() port.KeyRepository {
+	return newKeyRepository(r.db)
+}
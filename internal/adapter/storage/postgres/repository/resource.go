@@ -31,17 +31,37 @@ func (r *ResourceRepository) Create(ctx context.Context, data *domain.Resource)
 
 func (r *ResourceRepository) List(ctx context.Context, req *domain.ResourceListRequest) ([]*domain.Resource, int, error) {
 	var datas []*domain.Resource
-	err := r.db.Model(&domain.Resource{}).Find(&datas).Error
+	var count int
+	tx := r.db.Model(&domain.Resource{})
+	if req.Name != "" {
+		tx = tx.Where("name LIKE ?", "%"+req.Name+"%")
+	}
+	err := tx.Order("name").Find(&datas).Count(&count).Error
 	if err != nil {
 		return nil, 0, err
 	}
-	return datas, 0, nil
+	return datas, count, nil
 }
 
 func (r *ResourceRepository) Get(ctx context.Context, id string) (*domain.Resource, error) {
 	var data domain.Resource
 	if err := r.db.Model(&domain.Resource{}).
 		Take(&data, "id = ?", id).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *ResourceRepository) GetByName(ctx context.Context, name string) (*domain.Resource, error) {
+	var data domain.Resource
+	if err := r.db.Model(&domain.Resource{}).
+		Take(&data, "name = ?", name).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, domain.ErrDataNotFound
+		}
 		return nil, err
 	}
 	return &data, nil
@@ -56,6 +76,24 @@ func (r *ResourceRepository) Update(ctx context.Context, id string, req domain.M
 	return data, nil
 }
 
+// Delete removes the resource together with its scopes, scope claims, secrets and properties
 func (r *ResourceRepository) Delete(ctx context.Context, id string) error {
-	return r.db.Model(&domain.Resource{}).Where("id = ?", id).Delete(&domain.Resource{}).Error
+	tx := r.db.Begin()
+	scopes := tx.Model(&domain.ApiScope{}).Where("api_resource_id = ?", id).Select("id").QueryExpr()
+	steps := []func() error{
+		func() error {
+			return tx.Where("api_scope_id IN (?)", scopes).Delete(&domain.ApiScopeClaim{}).Error
+		},
+		func() error { return tx.Where("api_resource_id = ?", id).Delete(&domain.ApiScope{}).Error },
+		func() error { return tx.Where("api_resource_id = ?", id).Delete(&domain.ApiSecret{}).Error },
+		func() error { return tx.Where("api_resource_id = ?", id).Delete(&domain.ApiProperty{}).Error },
+		func() error { return tx.Where("id = ?", id).Delete(&domain.Resource{}).Error },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
 }
@@ -76,15 +76,6 @@ func (r *ClientSecretRepository) Get(ctx context.Context, id string) (*domain.Cl
 	return &data, nil
 }
 
-func (r *ClientSecretRepository) GetClientIDAndValue(ctx context.Context, clientID, value string) (*domain.ClientSecret, error) {
-	var data domain.ClientSecret
-	err := r.db.Model(&domain.ClientSecret{}).Take(&data, "client_id = ? AND value", clientID, value).Error
-	if err != nil {
-		return nil, err
-	}
-	return &data, nil
-}
-
 func (r *ClientSecretRepository) Update(ctx context.Context, id string, req domain.Map) (*domain.ClientSecret, error) {
 	data := &domain.ClientSecret{}
 	err := r.db.Model(&domain.ClientSecret{}).Where("id = ?", id).Updates(req).Take(&data).Error
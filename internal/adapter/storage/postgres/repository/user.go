@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"strconv"
+	"time"
 
 	"github.com/jinzhu/gorm"
 	"github.com/sugaml/authserver/internal/core/domain"
@@ -41,16 +43,42 @@ func (r *UserRepository) GetByID(ctx context.Context, id uint64) (*domain.User,
 	return user, err
 }
 
+// GetWithClaims gets a user by ID with its claims from the database
+func (r *UserRepository) GetWithClaims(ctx context.Context, id uint64) (*domain.User, error) {
+	user := &domain.User{}
+	err := r.db.Model(domain.User{}).Preload("UserClaims").Where("id = ?", id).Take(user).Error
+	if err != nil {
+		return nil, err
+	}
+	return user, err
+}
+
 // GetByEmailAndPassword gets a user by email from the database
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	user := &domain.User{}
 	err := r.db.Model(domain.User{}).Where("email = ?", email).Take(user).Error
 	if err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return nil, domain.ErrDataNotFound
+		}
 		return nil, err
 	}
 	return user, err
 }
 
+// ListRoleNames selects the normalized names of the roles of a user
+func (r *UserRepository) ListRoleNames(ctx context.Context, id uint64) ([]string, error) {
+	var names []string
+	err := r.db.Model(&domain.Role{}).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id::text").
+		Where("user_roles.user_id = ?", strconv.FormatUint(id, 10)).
+		Pluck("roles.normalized_name", &names).Error
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
 func (r *UserRepository) GetByMobileNum(ctx context.Context, mobileNum string) (*domain.User, error) {
 	user := &domain.User{}
 	err := r.db.Model(domain.User{}).Where("mobile_num = ?", mobileNum).Take(user).Error
@@ -73,6 +101,27 @@ func (r *UserRepository) SetPassword(ctx context.Context, id uint64, password st
 	return user, err
 }
 
+// SetLockout updates the failed sign in count and lockout end of a user
+func (r *UserRepository) SetLockout(ctx context.Context, id uint64, accessFailedCount int, lockoutEnd *time.Time) error {
+	return r.db.Model(domain.User{}).Where("id = ?", id).UpdateColumns(
+		map[string]interface{}{
+			"access_failed_count": accessFailedCount,
+			"lockout_end":         lockoutEnd,
+		},
+	).Error
+}
+
+// IncrementAccessFailedCount adds a failed sign in to a user and returns the new count
+func (r *UserRepository) IncrementAccessFailedCount(ctx context.Context, id uint64) (int, error) {
+	var count int
+	err := r.db.Raw("UPDATE users SET access_failed_count = access_failed_count + 1 WHERE id = ? RETURNING access_failed_count", id).
+		Row().Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // List lists all users from the database
 func (r *UserRepository) List(ctx context.Context, skip, limit uint64) ([]*domain.User, error) {
 	users := []*domain.User{}
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ApiScopeRequest declares a scope exposed by an api resource with the user claims it adds to tokens
+type ApiScopeRequest struct {
+	Name                    string   `json:"name"`
+	DisplayName             string   `json:"display_name"`
+	Description             string   `json:"description"`
+	Required                bool     `json:"required"`
+	Emphasize               bool     `json:"emphasize"`
+	ShowInDiscoveryDocument bool     `json:"show_in_discovery_document"`
+	Claims                  []string `json:"claims"`
+}
+
+// ApiScopeUpdateRequest replaces a scope, its claims included
+type ApiScopeUpdateRequest = ApiScopeRequest
+
+type ApiScopeResponse struct {
+	ID                      string    `json:"id"`
+	CreatedAt               time.Time `json:"created_at"`
+	Name                    string    `json:"name"`
+	DisplayName             string    `json:"display_name"`
+	Description             string    `json:"description"`
+	Required                bool      `json:"required"`
+	Emphasize               bool      `json:"emphasize"`
+	ShowInDiscoveryDocument bool      `json:"show_in_discovery_document"`
+	ApiResourceID           string    `json:"api_resource_id"`
+	Claims                  []string  `json:"claims"`
+}
+
+func (a *ApiScope) New(resourceID string, r *ApiScopeRequest) {
+	a.Name = r.Name
+	a.DisplayName = r.DisplayName
+	a.Description = r.Description
+	a.Required = r.Required
+	a.Emphasize = r.Emphasize
+	a.ShowInDiscoveryDocument = r.ShowInDiscoveryDocument
+	a.ApiResourceID = resourceID
+	a.ApiScopeClaims = nil
+	for _, claim := range r.Claims {
+		a.ApiScopeClaims = append(a.ApiScopeClaims, ApiScopeClaim{Type: claim, ApiScopeID: a.ID})
+	}
+}
+
+func (a *ApiScope) Validate() error {
+	if a.Name == "" {
+		return errors.New("required scope name")
+	}
+	if a.ApiResourceID == "" {
+		return errors.New("required api resource id")
+	}
+	for _, claim := range a.ApiScopeClaims {
+		if claim.Type == "" {
+			return errors.New("claim type must not be empty")
+		}
+	}
+	return nil
+}
+
+func (a *ApiScope) Response() *ApiScopeResponse {
+	res := &ApiScopeResponse{
+		ID:                      a.ID,
+		CreatedAt:               a.CreatedAt,
+		Name:                    a.Name,
+		DisplayName:             a.DisplayName,
+		Description:             a.Description,
+		Required:                a.Required,
+		Emphasize:               a.Emphasize,
+		ShowInDiscoveryDocument: a.ShowInDiscoveryDocument,
+		ApiResourceID:           a.ApiResourceID,
+		Claims:                  []string{},
+	}
+	for _, claim := range a.ApiScopeClaims {
+		res.Claims = append(res.Claims, claim.Type)
+	}
+	return res
+}
+
+// ApiSecretRequest adds a secret an api resource authenticates with at the introspection endpoint
+type ApiSecretRequest struct {
+	Description string     `json:"description"`
+	Value       string     `json:"value"`
+	Expiration  *time.Time `json:"expiration"`
+}
+
+type ApiSecretUpdateRequest struct {
+	Description string     `json:"description"`
+	Expiration  *time.Time `json:"expiration"`
+}
+
+// ApiSecretResponse carries the plain text Value only when the secret is created
+type ApiSecretResponse struct {
+	ID            string     `json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Description   string     `json:"description"`
+	Value         string     `json:"value,omitempty"`
+	Expiration    *time.Time `json:"expiration"`
+	ApiResourceID string     `json:"api_resource_id"`
+}
+
+func (a *ApiSecret) New(resourceID string, r *ApiSecretRequest) {
+	a.Description = r.Description
+	a.Expiration = r.Expiration
+	a.ApiResourceID = resourceID
+}
+
+func (a *ApiSecret) Validate() error {
+	if a.ApiResourceID == "" {
+		return errors.New("required api resource id")
+	}
+	if a.Expiration != nil && a.Expiration.Before(time.Now()) {
+		return errors.New("expiration must be in the future")
+	}
+	return nil
+}
+
+func (a *ApiSecret) Response() *ApiSecretResponse {
+	return &ApiSecretResponse{
+		ID:            a.ID,
+		CreatedAt:     a.CreatedAt,
+		Description:   a.Description,
+		Expiration:    a.Expiration,
+		ApiResourceID: a.ApiResourceID,
+	}
+}
+
+func (r *ApiSecretUpdateRequest) NewUpdate() Map {
+	return map[string]interface{}{
+		"description": r.Description,
+		"expiration":  r.Expiration,
+	}
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "crypto/x509"
+
+// ClientAssertionTypeJWTBearer is the client_assertion_type of a JWT client assertion, see RFC 7523 section 2.2
+const ClientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// ClientCredentials are the credentials a client presents with basic authorization,
+// a form posted secret, a client assertion or a TLS client certificate
+type ClientCredentials struct {
+	ClientID      string
+	Secret        string
+	AssertionType string
+	Assertion     string
+	Certificates  []*x509.Certificate
+}
+
+// HasCredentials reports whether the client presented anything beyond its id
+func (c *ClientCredentials) HasCredentials() bool {
+	return c.Secret != "" || c.Assertion != "" || len(c.Certificates) > 0
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Standard scopes
+const (
+	ScopeOpenID        = "openid"
+	ScopeOfflineAccess = "offline_access"
+)
+
+// IDTokenRequest carries what the token endpoint knows about the sign in behind an id_token
+type IDTokenRequest struct {
+	ClientID    string
+	SubjectID   string
+	Scope       string
+	AccessToken string
+	Nonce       string
+	AuthTime    time.Time
+	Sid         string
+}
+
+// HasScope reports whether the space separated scope contains name
+func HasScope(scope, name string) bool {
+	for _, s := range strings.Fields(scope) {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -38,6 +40,7 @@ type ClientRequest struct {
 	ClientUri         string
 	LogoutUri         string
 	EnabledLocalLogin bool
+	ClientPropertiesRequest
 }
 
 type ClientUpdateRequest struct {
@@ -51,21 +54,173 @@ type ClientUpdateRequest struct {
 	ClientUri         string
 	LogoutUri         string
 	EnabledLocalLogin bool
+	ClientPropertiesRequest
 }
 
-func (a *Client) New(r *ClientRequest) {
+// ClientPropertiesRequest carries the client properties managed through the client api.
+// A list left out of an update keeps the property, an empty list removes it.
+type ClientPropertiesRequest struct {
+	TokenExchangeAudience  []string `json:"token_exchange_audience"`
+	PostLogoutRedirectUris []string `json:"post_logout_redirect_uris"`
+	BackChannelLogoutUri   *string  `json:"backchannel_logout_uri"`
+}
+
+// Validate rejects list entries that are empty or would be split by the space separated property value
+func (r *ClientPropertiesRequest) Validate() error {
+	for _, aud := range r.TokenExchangeAudience {
+		if aud == "" || strings.ContainsAny(aud, " \t\n") {
+			return errors.New("token exchange audiences must not be empty or contain spaces")
+		}
+	}
+	backChannelLogoutUri := ""
+	if r.BackChannelLogoutUri != nil {
+		backChannelLogoutUri = *r.BackChannelLogoutUri
+	}
+	return validLogoutUris(r.PostLogoutRedirectUris, backChannelLogoutUri)
+}
+
+// Properties returns the value of every property the request sets, keyed by property key
+func (r *ClientPropertiesRequest) Properties() map[string]string {
+	props := map[string]string{}
+	if r.TokenExchangeAudience != nil {
+		props[ClientPropertyExchangeAudience] = strings.Join(r.TokenExchangeAudience, " ")
+	}
+	if r.PostLogoutRedirectUris != nil {
+		props[ClientPropertyPostLogoutRedirectUris] = strings.Join(r.PostLogoutRedirectUris, " ")
+	}
+	if r.BackChannelLogoutUri != nil {
+		props[ClientPropertyBackChannelLogoutUri] = *r.BackChannelLogoutUri
+	}
+	return props
+}
 
+func (a *Client) New(r *ClientRequest) {
+	a.Enabled = r.Enabled
 	a.ApplicationID = r.ApplicationID
 	a.ClientID = r.ClientID
 	if r.ClientID == "" {
 		a.ClientID = uuid.New().String()[:16]
 	}
+	a.ProtocolType = r.ProtocolType
+	a.ClientName = r.ClientName
+	a.Description = r.Description
+	a.ClientUri = r.ClientUri
+	a.LogoutUri = r.LogoutUri
+	a.EnabledLocalLogin = r.EnabledLocalLogin
+	for key, value := range r.Properties() {
+		if value != "" {
+			a.ClientProperties = append(a.ClientProperties, ClientProperty{ID: uuid.New().String(), Key: key, Value: value, ClientID: a.ID})
+		}
+	}
+}
+
+// NewRegistration fills an enabled client and its redirect uris, grant types, scopes and
+// token endpoint authentication method from dynamic registration metadata
+func (a *Client) NewRegistration(r *ClientRegistrationRequest) {
+	a.Enabled = true
+	a.ClientID = uuid.New().String()[:16]
+	a.ProtocolType = ProtocolTypeOpenIDConnect
+	a.SetRegistration(r)
+	// Dynamically registered clients are third-party clients
+	a.ClientProperties = append(a.ClientProperties, ClientProperty{ID: uuid.New().String(), Key: ClientPropertyRequireConsent, Value: "true", ClientID: a.ID})
+}
+
+// SetRegistration replaces the metadata of a dynamically registered client
+func (a *Client) SetRegistration(r *ClientRegistrationRequest) {
+	a.ClientName = r.ClientName
+	a.ClientUri = r.ClientUri
+	a.ClientRedirectUris = nil
+	for _, uri := range r.RedirectUris {
+		a.ClientRedirectUris = append(a.ClientRedirectUris, ClientRedirectUri{ID: uuid.New().String(), RedirectUri: uri, ClientID: a.ID})
+	}
+	a.ClientGrantTypes = nil
+	for _, gt := range r.GrantTypes {
+		a.ClientGrantTypes = append(a.ClientGrantTypes, ClientGrantType{ID: uuid.New().String(), GrantType: gt, ClientID: a.ID})
+	}
+	a.ClientScopes = nil
+	for _, s := range strings.Fields(r.Scope) {
+		a.ClientScopes = append(a.ClientScopes, ClientScope{ID: uuid.New().String(), Scope: s, ClientID: a.ID})
+	}
+	a.ClientProperties = []ClientProperty{{ID: uuid.New().String(), Key: ClientPropertyTokenEndpointAuthMethod, Value: r.TokenEndpointAuthMethod, ClientID: a.ID}}
+	if len(r.PostLogoutRedirectUris) > 0 {
+		a.ClientProperties = append(a.ClientProperties, ClientProperty{ID: uuid.New().String(), Key: ClientPropertyPostLogoutRedirectUris, Value: strings.Join(r.PostLogoutRedirectUris, " "), ClientID: a.ID})
+	}
+	if r.BackChannelLogoutUri != "" {
+		a.ClientProperties = append(a.ClientProperties, ClientProperty{ID: uuid.New().String(), Key: ClientPropertyBackChannelLogoutUri, Value: r.BackChannelLogoutUri, ClientID: a.ID})
+	}
+}
+
+// Property returns the value of a client property, or an empty string
+func (a *Client) Property(key string) string {
+	for _, p := range a.ClientProperties {
+		if p.Key == key {
+			return p.Value
+		}
+	}
+	return ""
 }
 
 func (a *Client) Validate() error {
 	return nil
 }
 
+// IsPublic reports whether the client has no secrets and so cannot authenticate itself
+func (a *Client) IsPublic() bool {
+	return len(a.ClientSecrets) == 0
+}
+
+// HasGrantType reports whether the client is registered for the grant type
+func (a *Client) HasGrantType(grantType string) bool {
+	for _, gt := range a.ClientGrantTypes {
+		if gt.GrantType == grantType {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRedirectUri reports whether the uri exactly matches a registered redirect uri
+func (a *Client) HasRedirectUri(uri string) bool {
+	for _, ru := range a.ClientRedirectUris {
+		if ru.RedirectUri == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScopes reports whether every space separated scope is registered for the client
+func (a *Client) HasScopes(scope string) bool {
+	for _, s := range strings.Fields(scope) {
+		found := false
+		for _, cs := range a.ClientScopes {
+			if cs.Scope == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// HasExchangeAudience reports whether the client may exchange tokens for the audience
+func (a *Client) HasExchangeAudience(audience string) bool {
+	for _, p := range a.ClientProperties {
+		if p.Key != ClientPropertyExchangeAudience {
+			continue
+		}
+		for _, aud := range strings.Fields(p.Value) {
+			if aud == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (r *ClientUpdateRequest) NewUpdate() Map {
 	return map[string]interface{}{}
 }
@@ -0,0 +1,53 @@
+package domain
+
+import "testing"
+
+func TestClientPropertiesRequest(t *testing.T) {
+	backChannel := "https://app.example.com/backchannel"
+	req := ClientPropertiesRequest{
+		TokenExchangeAudience:  []string{"orders", "billing"},
+		PostLogoutRedirectUris: []string{},
+		BackChannelLogoutUri:   &backChannel,
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	props := req.Properties()
+	want := map[string]string{
+		ClientPropertyExchangeAudience:       "orders billing",
+		ClientPropertyPostLogoutRedirectUris: "",
+		ClientPropertyBackChannelLogoutUri:   backChannel,
+	}
+	if len(props) != len(want) {
+		t.Fatalf("Properties() = %v, want %v", props, want)
+	}
+	for key, value := range want {
+		if props[key] != value {
+			t.Errorf("Properties()[%q] = %q, want %q", key, props[key], value)
+		}
+	}
+	if props := (&ClientPropertiesRequest{}).Properties(); len(props) != 0 {
+		t.Errorf("Properties() of an empty request = %v, want none", props)
+	}
+}
+
+func TestClientPropertiesRequestValidate(t *testing.T) {
+	insecure := "http://app.example.com/backchannel"
+	tests := []struct {
+		name string
+		req  ClientPropertiesRequest
+	}{
+		{"audience with space", ClientPropertiesRequest{TokenExchangeAudience: []string{"orders billing"}}},
+		{"empty audience", ClientPropertiesRequest{TokenExchangeAudience: []string{""}}},
+		{"http post logout redirect uri", ClientPropertiesRequest{PostLogoutRedirectUris: []string{"http://app.example.com/"}}},
+		{"post logout redirect uri with fragment", ClientPropertiesRequest{PostLogoutRedirectUris: []string{"https://app.example.com/#x"}}},
+		{"http back-channel logout uri", ClientPropertiesRequest{BackChannelLogoutUri: &insecure}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); err == nil {
+				t.Error("Validate() accepted the request")
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package domain
+
+// Confirmation is the cnf claim binding a token to a key held by the client, either the TLS
+// client certificate of RFC 8705 section 3.1 or the DPoP key of RFC 9449 section 6
+type Confirmation struct {
+	X5tS256 string `json:"x5t#S256,omitempty"`
+	JKT     string `json:"jkt,omitempty"`
+}
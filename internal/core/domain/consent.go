@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// ClientPropertyRequireConsent is the ClientProperty key that, set to "true", makes users consent
+// to the scopes a third-party client requests before it is issued an authorization code
+const ClientPropertyRequireConsent = "require_consent"
+
+// Consent decisions posted by the consent page
+const (
+	ConsentAllow = "allow"
+	ConsentDeny  = "deny"
+)
+
+// ConsentScope describes a requested IdentityResource or ApiScope on the consent page
+type ConsentScope struct {
+	Name        string
+	DisplayName string
+	Description string
+	Required    bool
+	Emphasize   bool
+	// Checked is set for required scopes and for scopes the user consented to before
+	Checked bool
+}
+
+// Consent lists the scopes a user is asked to consent to for a client
+type Consent struct {
+	IdentityScopes []ConsentScope
+	ApiScopes      []ConsentScope
+}
+
+// UserConsent is the data of a user_consent grant
+type UserConsent struct {
+	Scopes []string `json:"scopes"`
+}
+
+// UserConsentResponse represents a client the user consented to
+type UserConsentResponse struct {
+	ClientID   string    `json:"client_id"`
+	ClientName string    `json:"client_name"`
+	Scopes     []string  `json:"scopes"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// RequireConsent reports whether users consent to the scopes the client requests
+func (a *Client) RequireConsent() bool {
+	return a.Property(ClientPropertyRequireConsent) == "true"
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// DeviceAuthorizationResponse is returned by the device authorization endpoint
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationUri         string `json:"verification_uri"`
+	VerificationUriComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+// DeviceAuthorization is the state of a device code stored next to it
+type DeviceAuthorization struct {
+	ClientID   string    `json:"client_id"`
+	SubjectID  string    `json:"subject_id"`
+	Scope      string    `json:"scope"`
+	Interval   int64     `json:"interval"`
+	LastPolled time.Time `json:"last_polled"`
+	Denied     bool      `json:"denied"`
+	AuthTime   time.Time `json:"auth_time"`
+}
+
+// DeviceVerifyRequest represents the form of the device verification page
+type DeviceVerifyRequest struct {
+	UserCode string `form:"user_code"`
+	Email    string `form:"email"`
+	Password string `form:"password"`
+	Action   string `form:"action"`
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// DPoP identifiers, see RFC 9449
+const (
+	// DPoPHeader is the request header carrying a DPoP proof
+	DPoPHeader = "DPoP"
+	// TokenTypeDPoP is the token_type of sender-constrained access tokens and their authorization scheme
+	TokenTypeDPoP = "DPoP"
+	// DPoPProofType is the typ header of a DPoP proof JWT
+	DPoPProofType = "dpop+jwt"
+)
+
+// DPoPProof is a verified DPoP proof JWT
+type DPoPProof struct {
+	// JKT is the base64url SHA-256 JWK thumbprint of the proof key, see RFC 7638
+	JKT      string
+	JTI      string
+	IssuedAt time.Time
+}
+
+type dpopKey struct{}
+
+// WithDPoPProof returns a context carrying the proof for the access token generator
+func WithDPoPProof(ctx context.Context, proof *DPoPProof) context.Context {
+	return context.WithValue(ctx, dpopKey{}, proof)
+}
+
+// DPoPProofFromContext returns the proof carried by the context, if any
+func DPoPProofFromContext(ctx context.Context) *DPoPProof {
+	proof, _ := ctx.Value(dpopKey{}).(*DPoPProof)
+	return proof
+}
@@ -25,8 +25,12 @@ var (
 	ErrExpiredToken = errors.New("access token has expired")
 	// ErrInvalidToken is an error for when the access token is invalid
 	ErrInvalidToken = errors.New("access token is invalid")
+	// ErrRevokedToken is an error for when the access token has been revoked
+	ErrRevokedToken = errors.New("access token has been revoked")
 	// ErrInvalidCredentials is an error for when the credentials are invalid
 	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrLockedOut is an error for when the user is locked out after too many failed sign ins
+	ErrLockedOut = errors.New("user is locked out, try again later")
 	// ErrEmptyAuthorizationHeader is an error for when the authorization header is empty
 	ErrEmptyAuthorizationHeader = errors.New("authorization header is not provided")
 	// ErrInvalidAuthorizationHeader is an error for when the authorization header is invalid
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Token exchange identifiers, see RFC 8693 section 3
+const (
+	GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	TokenTypeAccessToken   = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+// ClientPropertyExchangeAudience is the ClientProperty key listing the space separated
+// audiences a client may request through a token exchange
+const ClientPropertyExchangeAudience = "token_exchange_audience"
+
+// TokenExchangeRequest is a token exchange request whose subject and actor tokens have been validated
+type TokenExchangeRequest struct {
+	ClientID         string
+	SubjectID        string
+	SubjectScope     string
+	SubjectExpiresAt time.Time
+	SubjectAudience  Audience
+	SubjectCnf       *Confirmation
+	SubjectActor     *Actor
+	ActorID          string
+	Audience         string
+	Scope            string
+	// Confirmation holds the TLS client certificate and DPoP key the caller proved possession of
+	Confirmation Confirmation
+}
+
+// Actor is the act claim naming the party acting on behalf of the subject. A nested act names
+// the party that acted before it in a delegation chain, see RFC 8693 section 4.1.
+type Actor struct {
+	Subject string `json:"sub"`
+	Actor   *Actor `json:"act,omitempty"`
+}
+
+// TokenExchange describes the access token issued by a token exchange
+type TokenExchange struct {
+	SubjectID string
+	Scope     string
+	Audience  string
+	Actor     *Actor
+	ExpiresIn time.Duration
+}
+
+type tokenExchangeKey struct{}
+
+// WithTokenExchange returns a context carrying the exchange for the access token generator
+func WithTokenExchange(ctx context.Context, te *TokenExchange) context.Context {
+	return context.WithValue(ctx, tokenExchangeKey{}, te)
+}
+
+// TokenExchangeFromContext returns the exchange carried by the context, if any
+func TokenExchangeFromContext(ctx context.Context) *TokenExchange {
+	te, _ := ctx.Value(tokenExchangeKey{}).(*TokenExchange)
+	return te
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"time"
+)
+
+// Persisted grant types
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRevokedToken      = "revoked_token"
+	GrantTypeDeviceCode        = "urn:ietf:params:oauth:grant-type:device_code"
+	GrantTypeAuthorizeToken    = "authorize_token"
+	GrantTypeReferenceToken    = "reference_token"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeClientAssertion   = "client_assertion"
+	GrantTypePushedRequest     = "pushed_authorization_request"
+	GrantTypeRegistrationToken = "registration_access_token"
+	GrantTypeUserSession       = "user_session"
+	GrantTypeSessionClient     = "session_client"
+	GrantTypeUserConsent       = "user_consent"
+	GrantTypeDPoPProof         = "dpop_proof"
+)
+
+// AuthorizeRequest represents the query or form parameters sent to /connect/authorize
+type AuthorizeRequest struct {
+	ResponseType        string   `form:"response_type"`
+	ClientID            string   `form:"client_id"`
+	RedirectURI         string   `form:"redirect_uri"`
+	Scope               string   `form:"scope"`
+	State               string   `form:"state"`
+	Nonce               string   `form:"nonce"`
+	CodeChallenge       string   `form:"code_challenge"`
+	CodeChallengeMethod string   `form:"code_challenge_method"`
+	RequestURI          string   `form:"request_uri"`
+	Email               string   `form:"email"`
+	Password            string   `form:"password"`
+	Consent             string   `form:"consent"`
+	ConsentScopes       []string `form:"consent_scopes"`
+}
+
+// RequestURIPrefix starts every request_uri issued by the pushed authorization request endpoint
+const RequestURIPrefix = "urn:ietf:params:oauth:request_uri:"
+
+// PushedAuthorizationResponse represents the response of /connect/par, see RFC 9126 section 2.2
+type PushedAuthorizationResponse struct {
+	RequestURI string `json:"request_uri"`
+	ExpiresIn  int    `json:"expires_in"`
+}
+
+// AuthorizationCode is the context stored next to an issued authorization code
+type AuthorizationCode struct {
+	ClientID            string    `json:"client_id"`
+	SubjectID           string    `json:"subject_id"`
+	RedirectURI         string    `json:"redirect_uri"`
+	Scope               string    `json:"scope"`
+	Nonce               string    `json:"nonce"`
+	CodeChallenge       string    `json:"code_challenge"`
+	CodeChallengeMethod string    `json:"code_challenge_method"`
+	AuthTime            time.Time `json:"auth_time"`
+	Sid                 string    `json:"sid,omitempty"`
+}
+
+// NewRefreshToken returns a refresh token handle prefixed with its family,
+// so a rotated token can be traced back to the tokens issued before it
+func NewRefreshToken(family, secret string) string {
+	return family + "." + secret
+}
+
+// RefreshTokenFamily returns the family of a refresh token handle
+func RefreshTokenFamily(refresh string) string {
+	family, _, _ := strings.Cut(refresh, ".")
+	return family
+}
+
+// GrantKey derives the persisted grant key from a handle and its grant type,
+// so raw codes and tokens are never stored as primary keys
+func GrantKey(handle, grantType string) string {
+	sum := sha256.Sum256([]byte(handle + ":" + grantType))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
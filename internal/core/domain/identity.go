@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// IdentityResourceRequest declares an identity scope with the user claims it releases and its properties
+type IdentityResourceRequest struct {
+	Enabled                 bool              `json:"enabled"`
+	Name                    string            `json:"name"`
+	DisplayName             string            `json:"display_name"`
+	Description             string            `json:"description"`
+	Required                bool              `json:"required"`
+	Emphasize               bool              `json:"emphasize"`
+	ShowInDiscoveryDocument bool              `json:"show_in_discovery_document"`
+	Claims                  []string          `json:"claims"`
+	Properties              map[string]string `json:"properties"`
+}
+
+// IdentityResourceUpdateRequest replaces an identity resource, its claims and properties included
+type IdentityResourceUpdateRequest = IdentityResourceRequest
+
+type IdentityResourceResponse struct {
+	ID                      string            `json:"id"`
+	CreatedAt               time.Time         `json:"created_at"`
+	Enabled                 bool              `json:"enabled"`
+	Name                    string            `json:"name"`
+	DisplayName             string            `json:"display_name"`
+	Description             string            `json:"description"`
+	Required                bool              `json:"required"`
+	Emphasize               bool              `json:"emphasize"`
+	ShowInDiscoveryDocument bool              `json:"show_in_discovery_document"`
+	Claims                  []string          `json:"claims"`
+	Properties              map[string]string `json:"properties"`
+}
+
+func (a *IdentityResource) New(r *IdentityResourceRequest) {
+	a.Enabled = r.Enabled
+	a.Name = r.Name
+	a.DisplayName = r.DisplayName
+	a.Description = r.Description
+	a.Required = r.Required
+	a.Emphasize = r.Emphasize
+	a.ShowInDiscoveryDocument = r.ShowInDiscoveryDocument
+	a.IdentityClaims = nil
+	for _, claim := range r.Claims {
+		a.IdentityClaims = append(a.IdentityClaims, IdentityClaim{Type: claim, IdentityResourceID: a.ID})
+	}
+	a.IdentityProperties = nil
+	for key, value := range r.Properties {
+		a.IdentityProperties = append(a.IdentityProperties, IdentityProperty{Key: key, Value: value, IdentityResourceID: a.ID})
+	}
+}
+
+func (a *IdentityResource) Validate() error {
+	if a.Name == "" {
+		return errors.New("required identity resource name")
+	}
+	for _, claim := range a.IdentityClaims {
+		if claim.Type == "" {
+			return errors.New("claim type must not be empty")
+		}
+	}
+	for _, property := range a.IdentityProperties {
+		if property.Key == "" {
+			return errors.New("property key must not be empty")
+		}
+	}
+	return nil
+}
+
+func (a *IdentityResource) Response() *IdentityResourceResponse {
+	res := &IdentityResourceResponse{
+		ID:                      a.ID,
+		CreatedAt:               a.CreatedAt,
+		Enabled:                 a.Enabled,
+		Name:                    a.Name,
+		DisplayName:             a.DisplayName,
+		Description:             a.Description,
+		Required:                a.Required,
+		Emphasize:               a.Emphasize,
+		ShowInDiscoveryDocument: a.ShowInDiscoveryDocument,
+		Claims:                  []string{},
+		Properties:              map[string]string{},
+	}
+	for _, claim := range a.IdentityClaims {
+		res.Claims = append(res.Claims, claim.Type)
+	}
+	for _, property := range a.IdentityProperties {
+		res.Properties[property.Key] = property.Value
+	}
+	return res
+}
+
+// StandardIdentityResources returns the scopes and claims defined by OpenID Connect Core sections
+// 5.4 and 11, seeded when the IdentityResource table is empty
+func StandardIdentityResources() []*IdentityResourceRequest {
+	resources := []*IdentityResourceRequest{
+		{
+			Name:        ScopeOpenID,
+			DisplayName: "Your user identifier",
+			Required:    true,
+			Claims:      []string{"sub"},
+		},
+		{
+			Name:        "profile",
+			DisplayName: "User profile",
+			Description: "Your user profile information (first name, last name, etc.)",
+			Emphasize:   true,
+			Claims: []string{
+				"name", "family_name", "given_name", "middle_name", "nickname", "preferred_username",
+				"profile", "picture", "website", "gender", "birthdate", "zoneinfo", "locale", "updated_at",
+			},
+		},
+		{
+			Name:        "email",
+			DisplayName: "Your email address",
+			Emphasize:   true,
+			Claims:      []string{"email", "email_verified"},
+		},
+		{
+			Name:        "phone",
+			DisplayName: "Your phone number",
+			Emphasize:   true,
+			Claims:      []string{"phone_number", "phone_number_verified"},
+		},
+		{
+			Name:        "address",
+			DisplayName: "Your postal address",
+			Emphasize:   true,
+			Claims:      []string{"address"},
+		},
+		{
+			Name:        ScopeOfflineAccess,
+			DisplayName: "Offline access",
+			Description: "Access to your applications and resources, even when you are offline",
+			Emphasize:   true,
+		},
+	}
+	for _, resource := range resources {
+		resource.Enabled = true
+		resource.ShowInDiscoveryDocument = true
+	}
+	return resources
+}
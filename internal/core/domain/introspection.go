@@ -0,0 +1,15 @@
+package domain
+
+// IntrospectionResponse is the RFC 7662 description of a token
+type IntrospectionResponse struct {
+	Active    bool     `json:"active"`
+	Scope     string   `json:"scope,omitempty"`
+	ClientID  string   `json:"client_id,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  []string `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	TokenType string   `json:"token_type,omitempty"`
+	// Cnf is the key the token is bound to, see RFC 8705 section 3.2 and RFC 9449 section 6.2
+	Cnf *Confirmation `json:"cnf,omitempty"`
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"crypto"
+	"time"
+)
+
+// Key uses and signing algorithms
+const (
+	KeyUseSignature = "sig"
+
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// Signing key lifecycle phases
+const (
+	KeyStatusPending = "pending"
+	KeyStatusActive  = "active"
+	KeyStatusRetired = "retired"
+	KeyStatusExpired = "expired"
+)
+
+// SigningKey is a decoded private key from the Key table
+type SigningKey struct {
+	ID         string
+	Version    int
+	Algorithm  string
+	PrivateKey crypto.Signer
+}
+
+// JSONWebKey represents the public half of a signing key as published in the JWKS
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet represents the document served at /.well-known/jwks.json
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+type KeyResponse struct {
+	ID        string    `json:"id"`
+	Version   int       `json:"version"`
+	Created   time.Time `json:"created"`
+	Use       string    `json:"use"`
+	Algorithm string    `json:"algorithm"`
+	Status    string    `json:"status"`
+}
@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ProtocolTypeOpenIDConnect is the protocol type of dynamically registered clients
+const ProtocolTypeOpenIDConnect = "oidc"
+
+// ClientPropertyTokenEndpointAuthMethod is the ClientProperty key holding the registered
+// token_endpoint_auth_method
+const ClientPropertyTokenEndpointAuthMethod = "token_endpoint_auth_method"
+
+// Token endpoint authentication methods accepted at registration
+const (
+	AuthMethodNone              = "none"
+	AuthMethodClientSecretBasic = "client_secret_basic"
+	AuthMethodClientSecretPost  = "client_secret_post"
+	AuthMethodClientSecretJwt   = "client_secret_jwt"
+	AuthMethodPrivateKeyJwt     = "private_key_jwt"
+)
+
+// registrationGrantTypes are the grant types a client may register for
+var registrationGrantTypes = map[string]bool{
+	"authorization_code":   true,
+	"refresh_token":        true,
+	"client_credentials":   true,
+	"password":             true,
+	GrantTypeDeviceCode:    true,
+	GrantTypeTokenExchange: true,
+}
+
+// confidentialGrantTypes are the grant types without user interaction, which only an
+// authenticated client may use
+var confidentialGrantTypes = map[string]bool{
+	"client_credentials":   true,
+	"password":             true,
+	GrantTypeTokenExchange: true,
+}
+
+// ClientMetadataError is a registration error, see RFC 7591 section 3.2.2
+type ClientMetadataError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *ClientMetadataError) Error() string {
+	return e.Description
+}
+
+func invalidClientMetadata(description string) error {
+	return &ClientMetadataError{Code: "invalid_client_metadata", Description: description}
+}
+
+// ClientRegistrationRequest represents the client metadata of RFC 7591 section 2
+type ClientRegistrationRequest struct {
+	RedirectUris            []string       `json:"redirect_uris"`
+	GrantTypes              []string       `json:"grant_types"`
+	ClientName              string         `json:"client_name,omitempty"`
+	ClientUri               string         `json:"client_uri,omitempty"`
+	Scope                   string         `json:"scope,omitempty"`
+	TokenEndpointAuthMethod string         `json:"token_endpoint_auth_method"`
+	Jwks                    *JSONWebKeySet `json:"jwks,omitempty"`
+	PostLogoutRedirectUris  []string       `json:"post_logout_redirect_uris,omitempty"`
+	BackChannelLogoutUri    string         `json:"backchannel_logout_uri,omitempty"`
+}
+
+// ClientRegistrationResponse represents the client information response of RFC 7591 section 3.2.1
+// and RFC 7592 section 3
+type ClientRegistrationResponse struct {
+	ClientID                string `json:"client_id"`
+	ClientSecret            string `json:"client_secret,omitempty"`
+	ClientIDIssuedAt        int64  `json:"client_id_issued_at"`
+	ClientSecretExpiresAt   int64  `json:"client_secret_expires_at"`
+	RegistrationAccessToken string `json:"registration_access_token,omitempty"`
+	RegistrationClientUri   string `json:"registration_client_uri,omitempty"`
+	ClientRegistrationRequest
+}
+
+// Prepare applies the defaults of RFC 7591 section 2 for omitted metadata
+func (r *ClientRegistrationRequest) Prepare() {
+	if len(r.GrantTypes) == 0 {
+		r.GrantTypes = []string{"authorization_code"}
+	}
+	if r.TokenEndpointAuthMethod == "" {
+		r.TokenEndpointAuthMethod = AuthMethodClientSecretBasic
+	}
+}
+
+// Validate checks the grant types, redirect uris and authentication method against the metadata they need
+func (r *ClientRegistrationRequest) Validate() error {
+	code := false
+	for _, gt := range r.GrantTypes {
+		if !registrationGrantTypes[gt] {
+			return invalidClientMetadata("unsupported grant type " + gt)
+		}
+		if confidentialGrantTypes[gt] && r.TokenEndpointAuthMethod == AuthMethodNone {
+			return invalidClientMetadata(gt + " requires client authentication")
+		}
+		code = code || gt == "authorization_code"
+	}
+	if code && len(r.RedirectUris) == 0 {
+		return &ClientMetadataError{Code: "invalid_redirect_uri", Description: "authorization_code requires redirect_uris"}
+	}
+	for _, uri := range r.RedirectUris {
+		if !validRedirectUri(uri) {
+			return &ClientMetadataError{Code: "invalid_redirect_uri", Description: "redirect uris must use https, http on a loopback host or a private-use scheme, without a fragment"}
+		}
+	}
+	if err := validLogoutUris(r.PostLogoutRedirectUris, r.BackChannelLogoutUri); err != nil {
+		return invalidClientMetadata(err.Error())
+	}
+	switch r.TokenEndpointAuthMethod {
+	case AuthMethodNone, AuthMethodClientSecretBasic, AuthMethodClientSecretPost, AuthMethodClientSecretJwt:
+		if r.Jwks != nil {
+			return invalidClientMetadata("jwks is only used with private_key_jwt")
+		}
+	case AuthMethodPrivateKeyJwt:
+		if r.Jwks == nil || len(r.Jwks.Keys) == 0 {
+			return invalidClientMetadata("private_key_jwt requires jwks")
+		}
+	default:
+		return invalidClientMetadata("unsupported token_endpoint_auth_method")
+	}
+	return nil
+}
+
+// validRedirectUri accepts https uris, http uris on a loopback host and private-use schemes in
+// reverse domain name notation such as com.example.app:/callback, see RFC 8252 sections 7.1 and 7.3
+func validRedirectUri(uri string) bool {
+	u, err := url.Parse(uri)
+	if err != nil || !u.IsAbs() || u.Fragment != "" {
+		return false
+	}
+	switch scheme := strings.ToLower(u.Scheme); scheme {
+	case "https":
+		return u.Host != ""
+	case "http":
+		return isLoopbackHost(u.Hostname())
+	default:
+		return strings.Contains(scheme, ".")
+	}
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
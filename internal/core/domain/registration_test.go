@@ -0,0 +1,55 @@
+package domain
+
+import "testing"
+
+func TestClientRegistrationRequestValidate(t *testing.T) {
+	redirect := []string{"https://app.example.com/callback"}
+	jwks := &JSONWebKeySet{Keys: []JSONWebKey{{Kty: "EC"}}}
+
+	tests := []struct {
+		name string
+		req  ClientRegistrationRequest
+		code string
+	}{
+		{"defaults", ClientRegistrationRequest{RedirectUris: redirect}, ""},
+		{"public authorization code", ClientRegistrationRequest{RedirectUris: redirect, TokenEndpointAuthMethod: AuthMethodNone}, ""},
+		{"public client credentials", ClientRegistrationRequest{GrantTypes: []string{"client_credentials"}, TokenEndpointAuthMethod: AuthMethodNone}, "invalid_client_metadata"},
+		{"public password", ClientRegistrationRequest{GrantTypes: []string{"password"}, TokenEndpointAuthMethod: AuthMethodNone}, "invalid_client_metadata"},
+		{"public token exchange", ClientRegistrationRequest{GrantTypes: []string{GrantTypeTokenExchange}, TokenEndpointAuthMethod: AuthMethodNone}, "invalid_client_metadata"},
+		{"public device code", ClientRegistrationRequest{GrantTypes: []string{GrantTypeDeviceCode}, TokenEndpointAuthMethod: AuthMethodNone}, ""},
+		{"confidential client credentials", ClientRegistrationRequest{GrantTypes: []string{"client_credentials"}}, ""},
+		{"unsupported grant type", ClientRegistrationRequest{GrantTypes: []string{"implicit"}, RedirectUris: redirect}, "invalid_client_metadata"},
+		{"missing redirect uris", ClientRegistrationRequest{}, "invalid_redirect_uri"},
+		{"relative redirect uri", ClientRegistrationRequest{RedirectUris: []string{"/callback"}}, "invalid_redirect_uri"},
+		{"loopback redirect uri", ClientRegistrationRequest{RedirectUris: []string{"http://127.0.0.1:8080/callback", "http://[::1]/callback", "http://localhost/callback"}}, ""},
+		{"private-use redirect uri", ClientRegistrationRequest{RedirectUris: []string{"com.example.app:/callback"}}, ""},
+		{"http redirect uri", ClientRegistrationRequest{RedirectUris: []string{"http://app.example.com/callback"}}, "invalid_redirect_uri"},
+		{"javascript redirect uri", ClientRegistrationRequest{RedirectUris: []string{"javascript:alert(1)"}}, "invalid_redirect_uri"},
+		{"data redirect uri", ClientRegistrationRequest{RedirectUris: []string{"data:text/html,<script>alert(1)</script>"}}, "invalid_redirect_uri"},
+		{"redirect uri with fragment", ClientRegistrationRequest{RedirectUris: []string{"https://app.example.com/callback#x"}}, "invalid_redirect_uri"},
+		{"private key jwt", ClientRegistrationRequest{RedirectUris: redirect, TokenEndpointAuthMethod: AuthMethodPrivateKeyJwt, Jwks: jwks}, ""},
+		{"private key jwt without jwks", ClientRegistrationRequest{RedirectUris: redirect, TokenEndpointAuthMethod: AuthMethodPrivateKeyJwt}, "invalid_client_metadata"},
+		{"shared secret with jwks", ClientRegistrationRequest{RedirectUris: redirect, Jwks: jwks}, "invalid_client_metadata"},
+		{"https logout uris", ClientRegistrationRequest{RedirectUris: redirect, PostLogoutRedirectUris: redirect, BackChannelLogoutUri: "https://app.example.com/backchannel"}, ""},
+		{"http post logout redirect uri", ClientRegistrationRequest{RedirectUris: redirect, PostLogoutRedirectUris: []string{"http://app.example.com/"}}, "invalid_client_metadata"},
+		{"javascript post logout redirect uri", ClientRegistrationRequest{RedirectUris: redirect, PostLogoutRedirectUris: []string{"javascript:alert(1)"}}, "invalid_client_metadata"},
+		{"relative back-channel logout uri", ClientRegistrationRequest{RedirectUris: redirect, BackChannelLogoutUri: "/backchannel"}, "invalid_client_metadata"},
+		{"unsupported auth method", ClientRegistrationRequest{RedirectUris: redirect, TokenEndpointAuthMethod: "tls_client_auth"}, "invalid_client_metadata"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Prepare()
+			err := tt.req.Validate()
+			if tt.code == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			merr, ok := err.(*ClientMetadataError)
+			if !ok || merr.Code != tt.code {
+				t.Errorf("Validate() error = %v, want %s", err, tt.code)
+			}
+		})
+	}
+}
@@ -1,6 +1,17 @@
 package domain
 
-import "time"
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// AccessTokenType is the typ header of JWT access tokens, see RFC 9068 section 2.1
+const AccessTokenType = "at+jwt"
+
+// ResourceParam is the token request parameter naming the resources an access token is requested
+// for, see RFC 8707 section 2
+const ResourceParam = "resource"
 
 type Resource struct {
 	BaseModel
@@ -32,12 +43,13 @@ type ResourceUpdateRequest struct {
 }
 
 type ResourceResponse struct {
-	ID          string    `json:"id"`
-	CreatedAt   time.Time `json:"created_at"`
-	Name        string    `json:"name"`
-	DisplayName string    `json:"display_name"`
-	Description string    `json:"description"`
-	Enabled     bool      `json:"enabled"`
+	ID          string              `json:"id"`
+	CreatedAt   time.Time           `json:"created_at"`
+	Name        string              `json:"name"`
+	DisplayName string              `json:"display_name"`
+	Description string              `json:"description"`
+	Enabled     bool                `json:"enabled"`
+	Scopes      []*ApiScopeResponse `json:"scopes,omitempty"`
 }
 
 func (a *Resource) New(r *ResourceRequest) {
@@ -47,7 +59,11 @@ func (a *Resource) New(r *ResourceRequest) {
 	a.Enabled = r.Enabled
 }
 
+// Validate requires a name, which is the audience of the access tokens issued for the resource
 func (a *Resource) Validate() error {
+	if a.Name == "" {
+		return errors.New("required resource name")
+	}
 	return nil
 }
 
@@ -59,3 +75,46 @@ func (a *ResourceUpdateRequest) NewUpdate() Map {
 	m["enabled"] = a.Enabled
 	return m
 }
+
+// Audience is the aud claim of an access token, encoded as a string for a single audience and as
+// an array otherwise, see RFC 7519 section 4.1.3
+type Audience []string
+
+// MarshalJSON implements json.Marshaler
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*a = multiple
+	return nil
+}
+
+// Contains reports whether the audience includes the name
+func (a Audience) Contains(name string) bool {
+	for _, aud := range a {
+		if aud == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessTokenAudience is the audience of an access token together with the scopes it carries
+type AccessTokenAudience struct {
+	Audience Audience
+	Scope    string
+}
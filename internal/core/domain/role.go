@@ -9,6 +9,9 @@ type RoleClaim struct {
 	ClaimValue string
 }
 
+// RoleAdmin is the normalized name of the role allowed to manage keys and resources
+const RoleAdmin = "ADMIN"
+
 type Role struct {
 	BaseModel
 	Name             string
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client property keys of OpenID Connect RP-initiated and back-channel logout
+const (
+	// ClientPropertyPostLogoutRedirectUris lists the space separated uris the end session
+	// endpoint may redirect to after signing the user out
+	ClientPropertyPostLogoutRedirectUris = "post_logout_redirect_uris"
+	// ClientPropertyBackChannelLogoutUri is the uri logout tokens are posted to. Clients without
+	// it are signed out through their LogoutUri in a front-channel iframe.
+	ClientPropertyBackChannelLogoutUri = "backchannel_logout_uri"
+)
+
+// BackChannelLogoutEvent is the event member of a logout token, see OpenID Connect Back-Channel Logout section 2.4
+const BackChannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// UserSession is the sign in session shared by every client the user authorized through the browser.
+// SessionID is the value of the session cookie and never leaves the browser, clients only see the
+// separate Sid in tokens and logout uris.
+type UserSession struct {
+	SessionID string
+	Sid       string
+	SubjectID string
+	AuthTime  time.Time
+}
+
+// EndSessionRequest represents the query or form parameters sent to /connect/endsession
+type EndSessionRequest struct {
+	IDTokenHint           string `form:"id_token_hint"`
+	ClientID              string `form:"client_id"`
+	PostLogoutRedirectURI string `form:"post_logout_redirect_uri"`
+	State                 string `form:"state"`
+}
+
+// EndSession describes what is left to do in the browser once a session has ended
+type EndSession struct {
+	// FrontChannelLogoutUris are loaded in iframes to sign the user out of the clients
+	FrontChannelLogoutUris []string
+	// RedirectURI is the validated post_logout_redirect_uri, with the state appended
+	RedirectURI string
+}
+
+// validLogoutUris rejects post logout redirect and back-channel logout uris that are not absolute
+// https uris without a fragment
+func validLogoutUris(postLogoutRedirectUris []string, backChannelLogoutUri string) error {
+	for _, uri := range postLogoutRedirectUris {
+		if !isHTTPSUri(uri) {
+			return errors.New("post logout redirect uris must be absolute https uris without a fragment")
+		}
+	}
+	if backChannelLogoutUri != "" && !isHTTPSUri(backChannelLogoutUri) {
+		return errors.New("back-channel logout uri must be an absolute https uri without a fragment")
+	}
+	return nil
+}
+
+// isHTTPSUri reports whether the uri is an absolute https uri with a host and without a fragment
+func isHTTPSUri(uri string) bool {
+	u, err := url.Parse(uri)
+	return err == nil && u.Scheme == "https" && u.Host != "" && u.Fragment == ""
+}
+
+// HasPostLogoutRedirectUri reports whether the uri is registered as a post logout redirect uri
+func (a *Client) HasPostLogoutRedirectUri(uri string) bool {
+	for _, registered := range strings.Fields(a.Property(ClientPropertyPostLogoutRedirectUris)) {
+		if registered == uri {
+			return true
+		}
+	}
+	return false
+}
@@ -1,14 +1,18 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
 // TokenPayload is an entity that represents the payload of the token
 type TokenPayload struct {
-	ID     uuid.UUID
-	UserID uint64
-	Role   UserRole
+	ID        uuid.UUID
+	UserID    uint64
+	Role      UserRole
+	IssuedAt  time.Time `json:"-"`
+	ExpiredAt time.Time `json:"-"`
 }
 
 // AuthResponse represents an authentication response body
@@ -73,6 +73,11 @@ type User struct {
 	UserTokens           []UserToken `gorm:"foreignkey:UserID" json:"user_tokens"`
 }
 
+// IsLockedOut reports whether lockout is enabled for the user and has not ended yet
+func (u *User) IsLockedOut() bool {
+	return u.LockoutEnabled && u.LockoutEnd != nil && u.LockoutEnd.After(time.Now())
+}
+
 // RegisterRequest represents the request body for creating a user
 type RegisterRequest struct {
 	Name           string `json:"name" example:"Sugam"`
@@ -149,56 +154,111 @@ type ClientScope struct {
 	ClientID string
 }
 
+// Client secret types
+const (
+	// SecretTypeSharedSecret is stored as a salted hash and sent as client_secret
+	SecretTypeSharedSecret = "SharedSecret"
+	// SecretTypeSigningSecret is stored encrypted and verifies client_secret_jwt assertions
+	SecretTypeSigningSecret = "SigningSecret"
+	// SecretTypeJsonWebKey is a public JWK or JWKS that verifies private_key_jwt assertions
+	SecretTypeJsonWebKey = "JsonWebKey"
+	// SecretTypeX509Name is the subject DN of a CA issued certificate for tls_client_auth
+	SecretTypeX509Name = "X509Name"
+	// SecretTypeX509Thumbprint is the base64url SHA-256 thumbprint of a certificate for self_signed_tls_client_auth
+	SecretTypeX509Thumbprint = "X509Thumbprint"
+)
+
+// ClientSecret is a client credential. Value holds the salted hash of a shared
+// secret, the encrypted signing secret or the public key, and is never serialized.
 type ClientSecret struct {
 	BaseModel
 	Description string
-	Value       string
+	Type        string
+	Value       string `json:"-"`
 	Expiration  *time.Time
 	ClientID    string
 }
 
+// IsSharedSecret reports whether the secret is compared against a presented client_secret
+func (a *ClientSecret) IsSharedSecret() bool {
+	return a.Type == "" || a.Type == SecretTypeSharedSecret
+}
+
 type ClientSecretRequest struct {
 	Description string     `json:"description"`
+	Type        string     `json:"type"`
 	Value       string     `json:"value"`
 	Expiration  *time.Time `json:"expiration"`
 	ClientID    string     `json:"client_id"`
 }
 
+// ClientSecretResponse carries the plain text Value only when the secret is created
 type ClientSecretResponse struct {
 	ID          string     `json:"id"`
 	CreatedAt   time.Time  `json:"created_at"`
 	Description string     `json:"description"`
-	Value       string     `json:"value"`
+	Type        string     `json:"type"`
+	Value       string     `json:"value,omitempty"`
 	Expiration  *time.Time `json:"expiration"`
 	ClientID    string     `json:"client_id"`
 }
 
 func (a *ClientSecret) New(r *ClientSecretRequest) {
 	a.Description = r.Description
-	a.Value = r.Value
+	a.Type = r.Type
+	if a.Type == "" {
+		a.Type = SecretTypeSharedSecret
+	}
 	a.ClientID = r.ClientID
 	a.Expiration = r.Expiration
 }
 
 func (a *ClientSecret) Validate() error {
+	if a.ClientID == "" {
+		return errors.New("required client id")
+	}
+	switch a.Type {
+	case SecretTypeSharedSecret, SecretTypeSigningSecret, SecretTypeJsonWebKey, SecretTypeX509Name, SecretTypeX509Thumbprint:
+	default:
+		return errors.New("unsupported client secret type")
+	}
+	if a.Expiration != nil && a.Expiration.Before(time.Now()) {
+		return errors.New("expiration must be in the future")
+	}
 	return nil
 }
 
+// Validate rejects an expiration in the past, like ClientSecret.Validate
+func (r *ClientSecretUpdateRequest) Validate() error {
+	if r.Expiration != nil && r.Expiration.Before(time.Now()) {
+		return errors.New("expiration must be in the future")
+	}
+	return nil
+}
+
+// NewUpdate allows the description and expiration to change, a field left out of the
+// request keeps its value. A secret value cannot be replaced; rotate it by creating a
+// new secret instead.
 func (r *ClientSecretUpdateRequest) NewUpdate() Map {
-	return map[string]interface{}{}
+	mp := map[string]interface{}{}
+	if r.Description != nil {
+		mp["description"] = *r.Description
+	}
+	if r.Expiration != nil {
+		mp["expiration"] = *r.Expiration
+	}
+	return mp
 }
 
 type ClientSecretListRequest struct {
 	ListRequest
 	Description string
-	Value       string
 	Expiration  *time.Time
 	ClientID    string
 }
 
 type ClientSecretUpdateRequest struct {
-	Description string
-	Value       string
+	Description *string
 	Expiration  *time.Time
 	ClientID    string
 }
@@ -237,8 +297,8 @@ type AuditMessage struct {
 }
 
 type DeviceCode struct {
-	UserCode     string
-	DeviceCode   string
+	UserCode     string `gorm:"primary_key"`
+	DeviceCode   string `gorm:"unique_index"`
 	SubjectID    string
 	ClientID     string
 	CreationTime time.Time
@@ -248,9 +308,9 @@ type DeviceCode struct {
 
 type Key struct {
 	ID                string `gorm:"primary_key"`
-	Version           int
+	Version           int    `gorm:"unique_index:idx_key_use_version"`
 	Created           time.Time
-	Use               string
+	Use               string `gorm:"unique_index:idx_key_use_version"`
 	Algorithm         string
 	IsX509Certificate bool
 	DataProtected     bool
@@ -259,11 +319,13 @@ type Key struct {
 
 type PersistedGrant struct {
 	Key          string `gorm:"primary_key"`
-	Type         string
-	SubjectID    string
-	ClientID     string
+	Type         string `gorm:"index"`
+	SubjectID    string `gorm:"index"`
+	ClientID     string `gorm:"index"`
+	FamilyID     string `gorm:"index"`
 	CreationTime time.Time
-	Expiration   time.Time
+	Expiration   time.Time `gorm:"index"`
+	ConsumedTime *time.Time
 	Data         string
 }
 
@@ -295,7 +357,7 @@ type ApiScopeClaim struct {
 type ApiSecret struct {
 	BaseModel
 	Description   string
-	Value         string
+	Value         string `json:"-"`
 	Expiration    *time.Time
 	ApiResourceID string
 }
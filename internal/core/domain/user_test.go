@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClientSecretUpdateRequestNewUpdate(t *testing.T) {
+	description := "rotated"
+	expiration := time.Now().Add(time.Hour)
+
+	mp := (&ClientSecretUpdateRequest{Description: &description}).NewUpdate()
+	if len(mp) != 1 || mp["description"] != description {
+		t.Errorf("NewUpdate() = %v, want only the description", mp)
+	}
+	mp = (&ClientSecretUpdateRequest{Expiration: &expiration}).NewUpdate()
+	if len(mp) != 1 || mp["expiration"] != expiration {
+		t.Errorf("NewUpdate() = %v, want only the expiration", mp)
+	}
+	if mp := (&ClientSecretUpdateRequest{}).NewUpdate(); len(mp) != 0 {
+		t.Errorf("NewUpdate() = %v, want nothing to update", mp)
+	}
+}
+
+func TestClientSecretUpdateRequestValidate(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	if err := (&ClientSecretUpdateRequest{Expiration: &past}).Validate(); err == nil {
+		t.Error("Validate() accepted an expiration in the past")
+	}
+	future := time.Now().Add(time.Hour)
+	if err := (&ClientSecretUpdateRequest{Expiration: &future}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package port
+
+import (
+	"context"
+
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+// type ApiScopeRepository interface is an interface for interacting with type ApiScope-related data
+type ApiScopeRepository interface {
+	ListDiscoverable(ctx context.Context) ([]*domain.ApiScope, error)
+	ListByNames(ctx context.Context, names []string) ([]*domain.ApiScope, error)
+	ListResourceNames(ctx context.Context, scopes []string) ([]string, error)
+	Create(ctx context.Context, data *domain.ApiScope) (*domain.ApiScope, error)
+	ListByApiResourceID(ctx context.Context, id string) ([]*domain.ApiScope, error)
+	ListEnabledByNames(ctx context.Context, names []string) ([]*domain.ApiScope, error)
+	Get(ctx context.Context, id string) (*domain.ApiScope, error)
+	Update(ctx context.Context, data *domain.ApiScope) (*domain.ApiScope, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// type ApiScopeService interface is an interface for interacting with type ApiScope-related data
+type ApiScopeService interface {
+	CreateApiScope(ctx context.Context, resourceID string, req *domain.ApiScopeRequest) (*domain.ApiScopeResponse, error)
+	ListApiScope(ctx context.Context, resourceID string) ([]*domain.ApiScopeResponse, error)
+	GetApiScope(ctx context.Context, resourceID, id string) (*domain.ApiScopeResponse, error)
+	UpdateApiScope(ctx context.Context, resourceID, id string, req *domain.ApiScopeUpdateRequest) (*domain.ApiScopeResponse, error)
+	DeleteApiScope(ctx context.Context, resourceID, id string) error
+	// ValidateScopes rejects scopes that are not an enabled identity resource, a scope of an
+	// enabled api resource or offline_access
+	ValidateScopes(ctx context.Context, scope string) error
+}
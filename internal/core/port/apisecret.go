@@ -0,0 +1,24 @@
+package port
+
+import (
+	"context"
+
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+// type ApiSecretRepository interface is an interface for interacting with type ApiSecret-related data
+type ApiSecretRepository interface {
+	ListByApiResourceID(ctx context.Context, id string) ([]*domain.ApiSecret, error)
+	Create(ctx context.Context, data *domain.ApiSecret) (*domain.ApiSecret, error)
+	Get(ctx context.Context, id string) (*domain.ApiSecret, error)
+	Update(ctx context.Context, id string, req domain.Map) (*domain.ApiSecret, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// type ApiSecretService interface is an interface for interacting with type ApiSecret-related data
+type ApiSecretService interface {
+	CreateApiSecret(ctx context.Context, resourceID string, req *domain.ApiSecretRequest) (*domain.ApiSecretResponse, error)
+	ListApiSecret(ctx context.Context, resourceID string) ([]*domain.ApiSecretResponse, error)
+	UpdateApiSecret(ctx context.Context, resourceID, id string, req *domain.ApiSecretUpdateRequest) (*domain.ApiSecretResponse, error)
+	DeleteApiSecret(ctx context.Context, resourceID, id string) error
+}
@@ -1,6 +1,10 @@
 package port
 
 import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/sugaml/authserver/internal/core/domain"
 )
 
@@ -13,3 +17,52 @@ type TokenService interface {
 	// VerifyToken verifies the token and returns the payload
 	VerifyToken(token string) (*domain.TokenPayload, error)
 }
+
+// AuthorizeService is an interface for interacting with authorization endpoint business logic
+type AuthorizeService interface {
+	// GetAuthorizeClient loads the client and checks the redirect uri it may be sent back to
+	GetAuthorizeClient(ctx context.Context, clientID, redirectURI string) (*domain.Client, error)
+	// ValidateAuthorizeRequest checks the response type, grant type, scopes and PKCE parameters
+	ValidateAuthorizeRequest(ctx context.Context, client *domain.Client, req *domain.AuthorizeRequest) error
+	// SaveAuthorizationCode stores the request context of an issued authorization code
+	SaveAuthorizationCode(ctx context.Context, code string, data *domain.AuthorizationCode, expiration time.Time) error
+	// VerifyAuthorizationCode checks the client and PKCE code verifier for an authorization code
+	VerifyAuthorizationCode(ctx context.Context, code, clientID, codeVerifier string) (*domain.AuthorizationCode, error)
+	// PushAuthorizationRequest validates and stores an authorization request behind a short lived request_uri
+	PushAuthorizationRequest(ctx context.Context, req *domain.AuthorizeRequest) (*domain.PushedAuthorizationResponse, error)
+	// GetPushedAuthorizationRequest returns the authorization request stored behind a request_uri for the client
+	GetPushedAuthorizationRequest(ctx context.Context, clientID, requestURI string) (*domain.AuthorizeRequest, error)
+	// DeletePushedAuthorizationRequest consumes a request_uri once an authorization code was issued for it
+	DeletePushedAuthorizationRequest(ctx context.Context, requestURI string) error
+}
+
+// IntrospectionService is an interface for interacting with token introspection business logic
+type IntrospectionService interface {
+	// AuthenticateIntrospection checks the client or api resource secret of the caller and
+	// returns the api resource name, which is empty when a client is calling
+	AuthenticateIntrospection(ctx context.Context, creds *domain.ClientCredentials) (string, error)
+	// DescribeRefreshToken returns an active refresh token without treating a rotated one as reuse
+	DescribeRefreshToken(ctx context.Context, refresh string) (*domain.IntrospectionResponse, error)
+	// CompleteIntrospection adds the token audience and hides tokens not meant for the api resource
+	CompleteIntrospection(ctx context.Context, resource string, data *domain.IntrospectionResponse) (*domain.IntrospectionResponse, error)
+}
+
+// RevocationService is an interface for interacting with token revocation business logic
+type RevocationService interface {
+	// AuthenticateClient checks the secret or client assertion of a confidential client or the id of a public client
+	AuthenticateClient(ctx context.Context, creds *domain.ClientCredentials) error
+	// RevokeRefreshToken removes the refresh token family and its access tokens
+	RevokeRefreshToken(ctx context.Context, refresh string) error
+	// RevokeToken adds the token to the denylist until it expires
+	RevokeToken(ctx context.Context, payload *domain.TokenPayload) error
+	// IsTokenRevoked reports whether the token id is on the denylist
+	IsTokenRevoked(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+// OpenIDService is an interface for interacting with OpenID Connect business logic
+type OpenIDService interface {
+	// CreateIDToken signs an id_token with the claims the requested scopes allow
+	CreateIDToken(ctx context.Context, req *domain.IDTokenRequest) (string, error)
+	// GetUserInfo returns the claims of the subject that the scopes allow
+	GetUserInfo(ctx context.Context, subjectID, scope string) (domain.Map, error)
+}
@@ -13,7 +13,11 @@ type ClientRepository interface {
 	ListByApplicationID(ctx context.Context, id string, req *domain.ClientListRequest) ([]*domain.Client, int, error)
 	Get(ctx context.Context, id string) (*domain.Client, error)
 	GetCliendID(ctx context.Context, clientID string) (*domain.Client, error)
+	GetDetailByClientID(ctx context.Context, clientID string) (*domain.Client, error)
 	Update(ctx context.Context, id string, req domain.Map) (*domain.Client, error)
+	CreateRegistration(ctx context.Context, data *domain.Client, token *domain.PersistedGrant) (*domain.Client, error)
+	UpdateRegistration(ctx context.Context, data *domain.Client) (*domain.Client, error)
+	UpdateProperties(ctx context.Context, id string, props map[string]string) error
 	UpdateIsActive(ctx context.Context, id string, isActive bool) (*domain.Client, error)
 	Delete(ctx context.Context, id string) error
 }
@@ -27,3 +31,15 @@ type ClientService interface {
 	UpdateClient(ctx context.Context, id string, req *domain.ClientUpdateRequest) (*domain.ClientResponse, error)
 	DeleteClient(ctx context.Context, id string) error
 }
+
+// RegistrationService is an interface for interacting with dynamic client registration business logic
+type RegistrationService interface {
+	// RegisterClient creates a client from its metadata and returns a registration access token for it
+	RegisterClient(ctx context.Context, req *domain.ClientRegistrationRequest) (*domain.ClientRegistrationResponse, error)
+	// GetClientRegistration returns the metadata of the client the registration access token belongs to
+	GetClientRegistration(ctx context.Context, clientID, token string) (*domain.ClientRegistrationResponse, error)
+	// UpdateClientRegistration replaces the metadata of the client the registration access token belongs to
+	UpdateClientRegistration(ctx context.Context, clientID, token string, req *domain.ClientRegistrationRequest) (*domain.ClientRegistrationResponse, error)
+	// DeleteClientRegistration deletes the client and its registration access token
+	DeleteClientRegistration(ctx context.Context, clientID, token string) error
+}
@@ -0,0 +1,19 @@
+package port
+
+import (
+	"context"
+
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+// ConsentService is an interface for interacting with user consent business logic
+type ConsentService interface {
+	// GetConsent describes the requested scopes the user has not consented to yet, or returns nil
+	GetConsent(ctx context.Context, subjectID string, client *domain.Client, scope string) (*domain.Consent, error)
+	// SaveConsent stores the consent decision and returns the scope that may be granted
+	SaveConsent(ctx context.Context, subjectID string, client *domain.Client, scope string, consented []string) (string, error)
+	// ListConsents returns the clients the user consented to
+	ListConsents(ctx context.Context, subjectID string) ([]*domain.UserConsentResponse, error)
+	// WithdrawConsent removes the consent of the user for the client and the tokens it was issued
+	WithdrawConsent(ctx context.Context, subjectID, clientID string) error
+}
@@ -0,0 +1,29 @@
+package port
+
+import (
+	"context"
+
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+// type DeviceCodeRepository interface is an interface for interacting with type DeviceCode-related data
+type DeviceCodeRepository interface {
+	Create(ctx context.Context, data *domain.DeviceCode) (*domain.DeviceCode, error)
+	GetByUserCode(ctx context.Context, userCode string) (*domain.DeviceCode, error)
+	GetByDeviceCode(ctx context.Context, deviceCode string) (*domain.DeviceCode, error)
+	Update(ctx context.Context, userCode string, req domain.Map) error
+	Redeem(ctx context.Context, userCode string) error
+	Delete(ctx context.Context, userCode string) error
+}
+
+// DeviceService is an interface for interacting with device authorization business logic
+type DeviceService interface {
+	// CreateDeviceAuthorization issues a device code and user code for the client
+	CreateDeviceAuthorization(ctx context.Context, clientID, scope string) (*domain.DeviceAuthorizationResponse, error)
+	// GetDeviceAuthorization returns the pending request and client behind a user code
+	GetDeviceAuthorization(ctx context.Context, userCode string) (*domain.Client, *domain.DeviceAuthorization, error)
+	// CompleteDeviceAuthorization records the decision of the signed in user
+	CompleteDeviceAuthorization(ctx context.Context, userCode, subjectID string, approved bool) error
+	// PollDeviceCode returns the approved request or the reason the device has to keep waiting
+	PollDeviceCode(ctx context.Context, clientID, deviceCode string) (*domain.DeviceAuthorization, error)
+}
@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+)
+
+// DiscoveryService is an interface for interacting with discovery metadata business logic
+type DiscoveryService interface {
+	// ListDiscoveryScopes returns the scopes and claims published in the discovery document
+	ListDiscoveryScopes(ctx context.Context) ([]string, []string, error)
+}
@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+// DPoPService is an interface for interacting with DPoP proof business logic
+type DPoPService interface {
+	// VerifyDPoPProof checks a DPoP proof for the method and uri, and the access token it is sent with, and rejects replays
+	VerifyDPoPProof(ctx context.Context, proof, method, uri, accessToken string) (*domain.DPoPProof, error)
+}
@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+// ExchangeService is an interface for interacting with token exchange business logic
+type ExchangeService interface {
+	// ExchangeToken applies the audience policy of the client and narrows the scope of the subject token
+	ExchangeToken(ctx context.Context, req *domain.TokenExchangeRequest) (*domain.TokenExchange, error)
+}
@@ -0,0 +1,33 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+// type PersistedGrantRepository interface is an interface for interacting with type PersistedGrant-related data
+type PersistedGrantRepository interface {
+	Create(ctx context.Context, data *domain.PersistedGrant) (*domain.PersistedGrant, error)
+	Get(ctx context.Context, key string) (*domain.PersistedGrant, error)
+	Delete(ctx context.Context, key string) error
+	Redeem(ctx context.Context, key string) error
+	Consume(ctx context.Context, key string, consumed time.Time) error
+	ListBySubject(ctx context.Context, subjectID, grantType string) ([]*domain.PersistedGrant, error)
+	DeleteBySubject(ctx context.Context, subjectID, clientID string, grantTypes []string) error
+	ListByFamily(ctx context.Context, familyID string) ([]*domain.PersistedGrant, error)
+	DeleteByFamily(ctx context.Context, familyID string) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// type AuditMessageRepository interface is an interface for interacting with type AuditMessage-related data
+type AuditMessageRepository interface {
+	Create(ctx context.Context, data *domain.AuditMessage) (*domain.AuditMessage, error)
+}
+
+// GrantService is an interface for interacting with persisted grant business logic
+type GrantService interface {
+	// RunGrantCleanup deletes expired persisted grants periodically until the context is cancelled
+	RunGrantCleanup(ctx context.Context)
+}
@@ -0,0 +1,28 @@
+package port
+
+import (
+	"context"
+
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+// type IdentityResourceRepository interface is an interface for interacting with type IdentityResource-related data
+type IdentityResourceRepository interface {
+	ListDiscoverable(ctx context.Context) ([]*domain.IdentityResource, error)
+	ListByNames(ctx context.Context, names []string) ([]*domain.IdentityResource, error)
+	Create(ctx context.Context, data *domain.IdentityResource) (*domain.IdentityResource, error)
+	List(ctx context.Context) ([]*domain.IdentityResource, error)
+	Get(ctx context.Context, id string) (*domain.IdentityResource, error)
+	GetByName(ctx context.Context, name string) (*domain.IdentityResource, error)
+	Update(ctx context.Context, data *domain.IdentityResource) (*domain.IdentityResource, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// type IdentityResourceService interface is an interface for interacting with type IdentityResource-related data
+type IdentityResourceService interface {
+	CreateIdentityResource(ctx context.Context, req *domain.IdentityResourceRequest) (*domain.IdentityResourceResponse, error)
+	ListIdentityResource(ctx context.Context) ([]*domain.IdentityResourceResponse, error)
+	GetIdentityResource(ctx context.Context, id string) (*domain.IdentityResourceResponse, error)
+	UpdateIdentityResource(ctx context.Context, id string, req *domain.IdentityResourceUpdateRequest) (*domain.IdentityResourceResponse, error)
+	DeleteIdentityResource(ctx context.Context, id string) error
+}
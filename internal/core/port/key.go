@@ -0,0 +1,32 @@
+package port
+
+import (
+	"context"
+
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+// type KeyRepository interface is an interface for interacting with type Key-related data
+type KeyRepository interface {
+	Create(ctx context.Context, data *domain.Key) (*domain.Key, error)
+	List(ctx context.Context, use string) ([]*domain.Key, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// KeyService is an interface for interacting with signing key business logic
+type KeyService interface {
+	// EnsureSigningKey creates the first signing key when the Key table is empty
+	EnsureSigningKey(ctx context.Context) error
+	// CreateSigningKey generates and stores a new signing key version
+	CreateSigningKey(ctx context.Context, algorithm string) (*domain.KeyResponse, error)
+	// ListSigningKeys returns the signing keys with their lifecycle phase
+	ListSigningKeys(ctx context.Context) ([]*domain.KeyResponse, error)
+	// RotateSigningKeys pre-publishes a new key when due, or always when forced, and removes expired keys
+	RotateSigningKeys(ctx context.Context, force bool) error
+	// RunKeyRotation runs RotateSigningKeys periodically until the context is done
+	RunKeyRotation(ctx context.Context)
+	// GetSigningKey returns the key used to sign new tokens
+	GetSigningKey(ctx context.Context) (*domain.SigningKey, error)
+	// GetJWKS returns the public halves of the signing keys
+	GetJWKS(ctx context.Context) (*domain.JSONWebKeySet, error)
+}
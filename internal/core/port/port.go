@@ -2,11 +2,27 @@ package port
 
 type IService interface {
 	ApplicationService
+	AuthorizeService
 	// TokenService
 	ClientService
+	ConsentService
 	CustomerService
+	DeviceService
+	DiscoveryService
+	DPoPService
+	ExchangeService
+	GrantService
+	IdentityResourceService
+	IntrospectionService
+	RevocationService
+	OpenIDService
+	RegistrationService
+	KeyService
 	ResourceService
+	ApiScopeService
+	ApiSecretService
 	RoleService
+	SessionService
 	ClientSecretService
 	TenantService
 	UserService
@@ -11,6 +11,7 @@ type ResourceRepository interface {
 	Create(ctx context.Context, data *domain.Resource) (*domain.Resource, error)
 	List(ctx context.Context, req *domain.ResourceListRequest) ([]*domain.Resource, int, error)
 	Get(ctx context.Context, id string) (*domain.Resource, error)
+	GetByName(ctx context.Context, name string) (*domain.Resource, error)
 	Update(ctx context.Context, id string, req domain.Map) (*domain.Resource, error)
 	Delete(ctx context.Context, id string) error
 }
@@ -22,4 +23,7 @@ type ResourceService interface {
 	GetResource(ctx context.Context, id string) (*domain.ResourceResponse, error)
 	UpdateResource(ctx context.Context, id string, req *domain.ResourceUpdateRequest) (*domain.ResourceResponse, error)
 	DeleteResource(ctx context.Context, id string) error
+	// GetAccessTokenAudience returns the resources an access token for the scope is meant for and
+	// the scopes it carries for them
+	GetAccessTokenAudience(ctx context.Context, scope string, resources []string) (*domain.AccessTokenAudience, error)
 }
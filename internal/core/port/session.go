@@ -0,0 +1,19 @@
+package port
+
+import (
+	"context"
+
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+// SessionService is an interface for interacting with sign in session and logout business logic
+type SessionService interface {
+	// CreateUserSession starts a sign in session for the subject
+	CreateUserSession(ctx context.Context, subjectID string) (*domain.UserSession, error)
+	// GetUserSession returns the unexpired sign in session
+	GetUserSession(ctx context.Context, sessionID string) (*domain.UserSession, error)
+	// AddSessionClient records a client the session authorized, so it is signed out with the session
+	AddSessionClient(ctx context.Context, sessionID, clientID string) error
+	// EndSession ends the session, notifies its back-channel clients and returns the front-channel logout uris
+	EndSession(ctx context.Context, sessionID string, req *domain.EndSessionRequest) (*domain.EndSession, error)
+}
@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/sugaml/authserver/internal/core/domain"
 )
@@ -14,12 +15,20 @@ type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) (*domain.User, error)
 	// GetByID selects a user by id
 	GetByID(ctx context.Context, id uint64) (*domain.User, error)
+	// GetWithClaims selects a user by id together with its claims
+	GetWithClaims(ctx context.Context, id uint64) (*domain.User, error)
 	// GetByEmail selects a user by email
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 	// GetByMobileNum selects a user by email
 	GetByMobileNum(ctx context.Context, mobileNum string) (*domain.User, error)
+	// ListRoleNames selects the normalized names of the roles of a user
+	ListRoleNames(ctx context.Context, id uint64) ([]string, error)
 	// SetPassword selects a user by email
 	SetPassword(ctx context.Context, id uint64, password string) (*domain.User, error)
+	// SetLockout updates the failed sign in count and lockout end of a user
+	SetLockout(ctx context.Context, id uint64, accessFailedCount int, lockoutEnd *time.Time) error
+	// IncrementAccessFailedCount adds a failed sign in to a user and returns the new count
+	IncrementAccessFailedCount(ctx context.Context, id uint64) (int, error)
 	// List selects a list of users with pagination
 	List(ctx context.Context, skip, limit uint64) ([]*domain.User, error)
 	// Update updates a user
@@ -34,6 +43,8 @@ type UserService interface {
 	RegisterUser(ctx context.Context, user *domain.RegisterRequest) (*domain.UserResponse, error)
 
 	LoginUser(ctx context.Context, user *domain.LoginRequest) (*domain.UserResponse, error)
+	// GetUserRoles returns the normalized names of the roles of a user
+	GetUserRoles(ctx context.Context, id uint64) ([]string, error)
 	// Get returns a user by id
 	GetUser(ctx context.Context, id uint64) (*domain.UserResponse, error)
 	// List returns a list of users with pagination
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+// CreateApiScope adds a scope with its claims to an api resource. Scope names are unique across
+// api resources and identity resources, so each scope names a single audience.
+func (s *Service) CreateApiScope(ctx context.Context, resourceID string, req *domain.ApiScopeRequest) (*domain.ApiScopeResponse, error) {
+	logrus.Info("package service CreateApiScope() ApiScope function called.")
+	if _, err := s.repo.Resource().Get(ctx, resourceID); err != nil {
+		return nil, err
+	}
+	data := &domain.ApiScope{}
+	data.New(resourceID, req)
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkScopeName(ctx, data.Name, ""); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.ApiScope().Create(ctx, data)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	return result.Response(), nil
+}
+
+// ListApiScope returns the scopes of an api resource with their claims
+func (s *Service) ListApiScope(ctx context.Context, resourceID string) ([]*domain.ApiScopeResponse, error) {
+	logrus.Info("package service ListApiScope() ApiScope function called.")
+	results, err := s.repo.ApiScope().ListByApiResourceID(ctx, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	datas := []*domain.ApiScopeResponse{}
+	for _, result := range results {
+		datas = append(datas, result.Response())
+	}
+	return datas, nil
+}
+
+// GetApiScope returns a scope of the api resource by id
+func (s *Service) GetApiScope(ctx context.Context, resourceID, id string) (*domain.ApiScopeResponse, error) {
+	logrus.Info("package service GetApiScope() ApiScope function called.")
+	result, err := s.getApiScope(ctx, resourceID, id)
+	if err != nil {
+		return nil, err
+	}
+	return result.Response(), nil
+}
+
+// UpdateApiScope replaces a scope of the api resource, its claims included
+func (s *Service) UpdateApiScope(ctx context.Context, resourceID, id string, req *domain.ApiScopeUpdateRequest) (*domain.ApiScopeResponse, error) {
+	logrus.Info("package service UpdateApiScope() ApiScope function called.")
+	data, err := s.getApiScope(ctx, resourceID, id)
+	if err != nil {
+		return nil, err
+	}
+	data.New(resourceID, req)
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkScopeName(ctx, data.Name, data.ID); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.ApiScope().Update(ctx, data)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	return result.Response(), nil
+}
+
+// DeleteApiScope removes a scope of the api resource with its claims
+func (s *Service) DeleteApiScope(ctx context.Context, resourceID, id string) error {
+	logrus.Info("package service DeleteApiScope() ApiScope function called.")
+	if _, err := s.getApiScope(ctx, resourceID, id); err != nil {
+		return err
+	}
+	return s.repo.ApiScope().Delete(ctx, id)
+}
+
+// ValidateScopes rejects with invalid_scope any scope that is not openid, offline_access, an enabled
+// identity resource or a scope of an enabled api resource. A standard OpenID Connect scope is only
+// rejected once an identity resource disables it, so databases without the seeded resources keep working.
+func (s *Service) ValidateScopes(ctx context.Context, scope string) error {
+	pending := map[string]bool{}
+	var names []string
+	for _, name := range strings.Fields(scope) {
+		if name == domain.ScopeOpenID || name == domain.ScopeOfflineAccess || pending[name] {
+			continue
+		}
+		pending[name] = true
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	identities, err := s.repo.IdentityResource().ListByNames(ctx, names)
+	if err != nil {
+		return err
+	}
+	for _, identity := range identities {
+		delete(pending, identity.Name)
+	}
+	apis, err := s.repo.ApiScope().ListEnabledByNames(ctx, names)
+	if err != nil {
+		return err
+	}
+	for _, api := range apis {
+		delete(pending, api.Name)
+	}
+	for _, standard := range domain.StandardIdentityResources() {
+		if !pending[standard.Name] {
+			continue
+		}
+		_, err := s.repo.IdentityResource().GetByName(ctx, standard.Name)
+		if err == domain.ErrDataNotFound {
+			delete(pending, standard.Name)
+		} else if err != nil {
+			return err
+		}
+	}
+	if len(pending) > 0 {
+		return errors.ErrInvalidScope
+	}
+	return nil
+}
+
+// getApiScope returns the scope when it belongs to the api resource
+func (s *Service) getApiScope(ctx context.Context, resourceID, id string) (*domain.ApiScope, error) {
+	result, err := s.repo.ApiScope().Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if result.ApiResourceID != resourceID {
+		return nil, domain.ErrDataNotFound
+	}
+	return result, nil
+}
+
+// checkScopeName rejects a scope name already used by another api scope, an identity resource or
+// a standard scope
+func (s *Service) checkScopeName(ctx context.Context, name, id string) error {
+	if name == domain.ScopeOpenID || name == domain.ScopeOfflineAccess {
+		return domain.ErrConflictingData
+	}
+	scopes, err := s.repo.ApiScope().ListByNames(ctx, []string{name})
+	if err != nil {
+		return domain.ErrInternal
+	}
+	for _, scope := range scopes {
+		if scope.ID != id {
+			return domain.ErrConflictingData
+		}
+	}
+	_, err = s.repo.IdentityResource().GetByName(ctx, name)
+	if err == nil {
+		return domain.ErrConflictingData
+	}
+	if err != domain.ErrDataNotFound {
+		return domain.ErrInternal
+	}
+	return nil
+}
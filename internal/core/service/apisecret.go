@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
+)
+
+// CreateApiSecret adds a generated secret to an api resource. It is stored hashed, so the
+// response is the only place the plain text is returned.
+func (s *Service) CreateApiSecret(ctx context.Context, resourceID string, req *domain.ApiSecretRequest) (*domain.ApiSecretResponse, error) {
+	logrus.Info("package service CreateApiSecret() ApiSecret function called.")
+	if _, err := s.repo.Resource().Get(ctx, resourceID); err != nil {
+		return nil, err
+	}
+	data := &domain.ApiSecret{}
+	data.New(resourceID, req)
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+	if req.Value != "" {
+		return nil, errors.New("secret values are generated and cannot be set")
+	}
+	value, err := util.RandomToken(clientSecretLength)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	hash, err := util.HashSecret(value)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	data.Value = hash
+	result, err := s.repo.ApiSecret().Create(ctx, data)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	res := result.Response()
+	res.Value = value
+	return res, nil
+}
+
+// ListApiSecret returns the secrets of an api resource without their values
+func (s *Service) ListApiSecret(ctx context.Context, resourceID string) ([]*domain.ApiSecretResponse, error) {
+	logrus.Info("package service ListApiSecret() ApiSecret function called.")
+	results, err := s.repo.ApiSecret().ListByApiResourceID(ctx, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	datas := []*domain.ApiSecretResponse{}
+	for _, result := range results {
+		datas = append(datas, result.Response())
+	}
+	return datas, nil
+}
+
+// UpdateApiSecret changes the description and expiration of a secret of the api resource
+func (s *Service) UpdateApiSecret(ctx context.Context, resourceID, id string, req *domain.ApiSecretUpdateRequest) (*domain.ApiSecretResponse, error) {
+	logrus.Info("package service UpdateApiSecret() ApiSecret function called.")
+	if _, err := s.getApiSecret(ctx, resourceID, id); err != nil {
+		return nil, err
+	}
+	if req.Expiration != nil && req.Expiration.Before(time.Now()) {
+		return nil, errors.New("expiration must be in the future")
+	}
+	result, err := s.repo.ApiSecret().Update(ctx, id, req.NewUpdate())
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	return result.Response(), nil
+}
+
+// DeleteApiSecret removes a secret of the api resource
+func (s *Service) DeleteApiSecret(ctx context.Context, resourceID, id string) error {
+	logrus.Info("package service DeleteApiSecret() ApiSecret function called.")
+	if _, err := s.getApiSecret(ctx, resourceID, id); err != nil {
+		return err
+	}
+	return s.repo.ApiSecret().Delete(ctx, id)
+}
+
+// getApiSecret returns the secret when it belongs to the api resource
+func (s *Service) getApiSecret(ctx context.Context, resourceID, id string) (*domain.ApiSecret, error) {
+	result, err := s.repo.ApiSecret().Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if result.ApiResourceID != resourceID {
+		return nil, domain.ErrDataNotFound
+	}
+	return result, nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+// assertionKey is a key that may have signed a client assertion
+type assertionKey struct {
+	kid string
+	key interface{}
+}
+
+// verifyClientAssertion checks the signature, issuer, subject, audience, expiry and jti of a
+// client assertion, see RFC 7523 section 3. Each jti is accepted once until the assertion expires.
+func (s *Service) verifyClientAssertion(ctx context.Context, client *domain.Client, creds *domain.ClientCredentials) error {
+	if creds.AssertionType != domain.ClientAssertionTypeJWTBearer {
+		return errors.ErrInvalidClient
+	}
+	claims, err := s.parseClientAssertion(client, creds.Assertion)
+	if err != nil {
+		return err
+	}
+	iss, _ := claims["iss"].(string)
+	sub, _ := claims["sub"].(string)
+	jti, _ := claims["jti"].(string)
+	exp, ok := claims["exp"].(float64)
+	if iss != client.ClientID || sub != client.ClientID || jti == "" || !ok || !s.isAssertionAudience(claims["aud"]) {
+		return errors.ErrInvalidClient
+	}
+	// The jti is the primary key of the grant, so a replayed assertion fails to insert
+	_, err = s.repo.PersistedGrant().Create(ctx, &domain.PersistedGrant{
+		Key:          domain.GrantKey(client.ClientID+":"+jti, domain.GrantTypeClientAssertion),
+		Type:         domain.GrantTypeClientAssertion,
+		ClientID:     client.ClientID,
+		CreationTime: time.Now(),
+		Expiration:   time.Unix(int64(exp), 0),
+	})
+	if err != nil {
+		return errors.ErrInvalidClient
+	}
+	return nil
+}
+
+// parseClientAssertion verifies the assertion with the unexpired signing secrets and public keys of the client
+func (s *Service) parseClientAssertion(client *domain.Client, assertion string) (jwt.MapClaims, error) {
+	for _, cs := range client.ClientSecrets {
+		if cs.Expiration != nil && cs.Expiration.Before(time.Now()) {
+			continue
+		}
+		keys, err := s.assertionKeys(&cs)
+		if err != nil {
+			continue
+		}
+		for _, ak := range keys {
+			claims := jwt.MapClaims{}
+			// The key types of jwt-go reject a signature made with another algorithm family
+			_, err := jwt.ParseWithClaims(assertion, claims, func(t *jwt.Token) (interface{}, error) {
+				if kid, _ := t.Header["kid"].(string); kid != "" && ak.kid != "" && kid != ak.kid {
+					return nil, errors.ErrInvalidClient
+				}
+				return ak.key, nil
+			})
+			if err == nil {
+				return claims, nil
+			}
+		}
+	}
+	return nil, errors.ErrInvalidClient
+}
+
+// assertionKeys returns the verification keys held by a signing secret or public key secret
+func (s *Service) assertionKeys(cs *domain.ClientSecret) ([]assertionKey, error) {
+	switch cs.Type {
+	case domain.SecretTypeSigningSecret:
+		secret, err := util.Unprotect(s.config.KeySecret, cs.Value)
+		if err != nil {
+			return nil, err
+		}
+		return []assertionKey{{key: []byte(secret)}}, nil
+	case domain.SecretTypeJsonWebKey:
+		jwks, err := parseJSONWebKeys(cs.Value)
+		if err != nil {
+			return nil, err
+		}
+		var keys []assertionKey
+		for _, jwk := range jwks {
+			key, err := util.ParseJSONWebKey(jwk)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, assertionKey{kid: jwk.Kid, key: key})
+		}
+		return keys, nil
+	}
+	return nil, errors.ErrInvalidClient
+}
+
+// parseJSONWebKeys reads a single JWK or a JWKS document
+func parseJSONWebKeys(value string) ([]domain.JSONWebKey, error) {
+	var set domain.JSONWebKeySet
+	if err := json.Unmarshal([]byte(value), &set); err == nil && len(set.Keys) > 0 {
+		return set.Keys, nil
+	}
+	var jwk domain.JSONWebKey
+	if err := json.Unmarshal([]byte(value), &jwk); err != nil {
+		return nil, err
+	}
+	return []domain.JSONWebKey{jwk}, nil
+}
+
+// isAssertionAudience accepts the issuer or one of its /connect endpoints as the audience
+func (s *Service) isAssertionAudience(aud interface{}) bool {
+	issuer := s.config.Issuer
+	match := func(v interface{}) bool {
+		value, _ := v.(string)
+		return value != "" && (value == issuer || strings.HasPrefix(value, issuer+"/connect/"))
+	}
+	if values, ok := aud.([]interface{}); ok {
+		for _, v := range values {
+			if match(v) {
+				return true
+			}
+		}
+		return false
+	}
+	return match(aud)
+}
+
+// authenticateClientCredentials verifies a client assertion, or else the presented shared
+// secret, or else the TLS client certificate
+func (s *Service) authenticateClientCredentials(ctx context.Context, client *domain.Client, creds *domain.ClientCredentials) error {
+	if creds.Assertion != "" {
+		return s.verifyClientAssertion(ctx, client, creds)
+	}
+	if creds.Secret != "" {
+		if verifyClientSecret(client, creds.Secret) {
+			return nil
+		}
+		return errors.ErrInvalidClient
+	}
+	if s.verifyClientCertificate(client, creds.Certificates) {
+		return nil
+	}
+	return errors.ErrInvalidClient
+}
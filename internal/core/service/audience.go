@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+// GetAccessTokenAudience returns the api resources of the scopes as the audience of an access token.
+// When resources are requested the audience is limited to them, and the api scopes of other
+// resources are left out. Scopes without an api resource, such as openid, are always kept.
+func (s *Service) GetAccessTokenAudience(ctx context.Context, scope string, resources []string) (*domain.AccessTokenAudience, error) {
+	logrus.Info("package service GetAccessTokenAudience() resource function called.")
+	scopes := strings.Fields(scope)
+	if len(resources) == 0 {
+		if len(scopes) == 0 {
+			return &domain.AccessTokenAudience{Scope: scope}, nil
+		}
+		names, err := s.repo.ApiScope().ListResourceNames(ctx, scopes)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.AccessTokenAudience{Audience: names, Scope: scope}, nil
+	}
+	requested := map[string]string{}
+	for _, name := range resources {
+		resource, err := s.repo.Resource().GetByName(ctx, name)
+		if err != nil || !resource.Enabled {
+			return nil, errInvalidTarget
+		}
+		requested[resource.ID] = resource.Name
+	}
+	apiScopes, err := s.repo.ApiScope().ListByNames(ctx, scopes)
+	if err != nil {
+		return nil, err
+	}
+	scopeResources := map[string]string{}
+	for _, api := range apiScopes {
+		scopeResources[api.Name] = api.ApiResourceID
+	}
+	granted := map[string]bool{}
+	var kept []string
+	for _, name := range scopes {
+		id, ok := scopeResources[name]
+		if !ok {
+			kept = append(kept, name)
+			continue
+		}
+		if _, ok := requested[id]; ok {
+			granted[id] = true
+			kept = append(kept, name)
+		}
+	}
+	// Every requested resource must be covered by one of the granted scopes
+	result := &domain.AccessTokenAudience{Scope: strings.Join(kept, " ")}
+	for _, name := range resources {
+		for id, resource := range requested {
+			if resource == name && !granted[id] {
+				return nil, errInvalidTarget
+			}
+		}
+		if !result.Audience.Contains(name) {
+			result.Audience = append(result.Audience, name)
+		}
+	}
+	return result, nil
+}
@@ -2,78 +2,241 @@ package service
 
 import (
 	"context"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/google/uuid"
 	"github.com/sugaml/authserver/internal/adapter/storage/postgres/repository"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/port"
+	"github.com/sugaml/authserver/internal/core/util"
 	"gopkg.in/oauth2.v3"
+	"gopkg.in/oauth2.v3/errors"
 	"gopkg.in/oauth2.v3/models"
+	"gopkg.in/oauth2.v3/server"
 )
 
 type ClientStotre struct {
-	repo repository.IRepository
+	repo   repository.IRepository
+	auth   port.RevocationService
+	scopes port.ApiScopeService
 }
 
-// JWTClaims defines the structure of JWT token claims
+// JWTClaims defines the structure of JWT access token claims, see RFC 9068 section 2.2.
+// Audience replaces the single aud of StandardClaims.
 type JWTClaims struct {
-	ClientID string `json:"client_id"`
-	Scope    string `json:"scope"`
+	ClientID string               `json:"client_id"`
+	Scope    string               `json:"scope"`
+	Audience domain.Audience      `json:"aud,omitempty"`
+	Act      *domain.Actor        `json:"act,omitempty"`
+	Cnf      *domain.Confirmation `json:"cnf,omitempty"`
 	jwt.StandardClaims
 }
 
-var jwtSecret = []byte("supersecretkey")
-
-func newClientStotreService(repo repository.IRepository) *ClientStotre {
+func newClientStotreService(repo repository.IRepository, auth port.RevocationService, scopes port.ApiScopeService) *ClientStotre {
 	return &ClientStotre{
-		repo: repo,
+		repo:   repo,
+		auth:   auth,
+		scopes: scopes,
 	}
 }
 
-// GetByID retrieves a client by its ID from the database
+// GetByID retrieves a client by its ID from the database. Secrets are verified by
+// ClientInfoHandler, so the client carries the empty secret the handler passes on.
 func (s *ClientStotre) GetByID(id string) (oauth2.ClientInfo, error) {
-	domain := "http://localhost:9094"
-	secret := ""
 	result, err := s.repo.Client().GetCliendID(context.Background(), id)
 	if err != nil {
-		return nil, err
-	}
-	results, _, err := s.repo.ClientSecret().ListByClientID(context.Background(), result.ID)
-	if err != nil {
-		return nil, err
-	}
-	for _, res := range results {
-		secret = res.Value
+		return nil, errors.ErrInvalidClient
 	}
 	return &models.Client{
-		ID:     result.ID,
-		Secret: secret,
-		Domain: domain,
+		ID:     result.ClientID,
+		Domain: result.ClientUri,
 	}, nil
 }
 
-type JWTAccessGenerate struct{}
+// ClientInfoHandler authenticates the client from the form or basic authorization against
+// any of its unexpired secrets, or from a client assertion or TLS client certificate. Public clients may omit the
+// secret for the authorization code, refresh token and password grants only, and are rejected when they present a
+// secret or client assertion.
+func (s *ClientStotre) ClientInfoHandler(r *http.Request) (string, string, error) {
+	creds := util.ParseClientCredentials(r)
+	if creds.ClientID == "" {
+		return "", "", errors.ErrInvalidClient
+	}
+	result, err := s.repo.Client().GetDetailByClientID(r.Context(), creds.ClientID)
+	if err != nil || !result.Enabled {
+		return "", "", errors.ErrInvalidClient
+	}
+	if !result.IsPublic() || creds.HasCredentials() {
+		if err := s.auth.AuthenticateClient(r.Context(), creds); err != nil {
+			return "", "", errors.ErrInvalidClient
+		}
+	}
+	if result.IsPublic() {
+		switch oauth2.GrantType(r.FormValue("grant_type")) {
+		case oauth2.AuthorizationCode, oauth2.Refreshing, oauth2.PasswordCredentials:
+		default:
+			return "", "", errors.ErrInvalidClient
+		}
+	}
+	return creds.ClientID, "", nil
+}
+
+// ClientAuthorizedHandler rejects disabled clients with invalid_client and grant types the
+// client is not registered for with unauthorized_client. Refresh tokens are only issued for
+// grants the client was allowed, so refreshing needs no registration of its own.
+func (s *ClientStotre) ClientAuthorizedHandler(clientID string, grant oauth2.GrantType) (bool, error) {
+	result, err := s.repo.Client().GetDetailByClientID(context.Background(), clientID)
+	if err != nil || !result.Enabled {
+		return false, errors.ErrInvalidClient
+	}
+	if grant == oauth2.Refreshing {
+		return true, nil
+	}
+	return result.HasGrantType(grant.String()), nil
+}
+
+// ClientScopeHandler rejects scopes outside the ClientScopes of the client, and scopes no enabled
+// identity or api resource defines, with invalid_scope
+func (s *ClientStotre) ClientScopeHandler(clientID, scope string) (bool, error) {
+	ctx := context.Background()
+	result, err := s.repo.Client().GetDetailByClientID(ctx, clientID)
+	if err != nil || !result.Enabled {
+		return false, errors.ErrInvalidClient
+	}
+	if !result.HasScopes(scope) {
+		return false, nil
+	}
+	if err := s.scopes.ValidateScopes(ctx, scope); err != nil {
+		if err == errors.ErrInvalidScope {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// refreshingScopeHandler only allows a refresh request to narrow the scope, every requested
+// scope must have been granted with the refresh token, see RFC 6749 section 6
+func refreshingScopeHandler(newScope, oldScope string) (bool, error) {
+	for _, scope := range strings.Fields(newScope) {
+		if !domain.HasScope(oldScope, scope) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
 
-// Token implements the AccessGenerate interface
+// passwordAuthorizationHandler signs the resource owner in through LoginUser, so the
+// password grant shares the lockout policy of the sign in form. Wrong credentials and
+// locked out users get invalid_grant.
+func passwordAuthorizationHandler(svc port.UserService) server.PasswordAuthorizationHandler {
+	return func(username, password string) (string, error) {
+		if username == "" || password == "" {
+			return "", nil
+		}
+		result, err := svc.LoginUser(context.Background(), &domain.LoginRequest{
+			Email:    username,
+			Password: password,
+		})
+		if err == domain.ErrInvalidCredentials || err == domain.ErrLockedOut {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatUint(uint64(result.ID), 10), nil
+	}
+}
+
+// JWTAccessGenerate signs access tokens with the current key from the Key table
+type JWTAccessGenerate struct {
+	keys      port.KeyService
+	resources port.ResourceService
+	issuer    string
+}
+
+// Token implements the AccessGenerate interface. Access tokens follow the JWT profile of RFC 9068,
+// their audience is the api resources of the scopes or the resources requested with RFC 8707
+// resource indicators.
 func (g *JWTAccessGenerate) Token(data *oauth2.GenerateBasic, isGenRefresh bool) (access, refresh string, err error) {
+	ctx := context.Background()
+	var resources []string
+	if data.Request != nil {
+		ctx = data.Request.Context()
+		if err := data.Request.ParseForm(); err == nil {
+			resources = data.Request.Form[domain.ResourceParam]
+		}
+	}
+	key, err := g.keys.GetSigningKey(ctx)
+	if err != nil {
+		return "", "", err
+	}
 	now := time.Now()
+	exp := data.TokenInfo.GetAccessExpiresIn()
+	if exp <= 0 {
+		exp = time.Hour
+	}
+	// The client is the subject of tokens issued for itself, see RFC 9068 section 2.2
+	subject := data.UserID
+	if subject == "" {
+		subject = data.Client.GetID()
+	}
 	claims := JWTClaims{
 		ClientID: data.Client.GetID(),
-		Scope:    data.TokenInfo.GetScope(),
 		StandardClaims: jwt.StandardClaims{
+			Issuer:    g.issuer,
+			Subject:   subject,
+			Id:        uuid.New().String(),
 			IssuedAt:  now.Unix(),
-			ExpiresAt: now.Add(time.Hour).Unix(),
+			ExpiresAt: now.Add(exp).Unix(),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	access, err = token.SignedString(jwtSecret)
+	// The stored token keeps every granted scope so its refresh token can be used for other resources
+	aud, err := g.resources.GetAccessTokenAudience(ctx, data.TokenInfo.GetScope(), resources)
+	if err != nil {
+		return "", "", err
+	}
+	claims.Scope = aud.Scope
+	claims.Audience = aud.Audience
+	if data.Request != nil {
+		if te := domain.TokenExchangeFromContext(data.Request.Context()); te != nil {
+			claims.Subject = te.SubjectID
+			claims.Audience = domain.Audience{te.Audience}
+			claims.Act = te.Actor
+		}
+		// Bind the token to the TLS client certificate, see RFC 8705 section 3,
+		// and to the DPoP proof key, see RFC 9449 section 6
+		cnf := domain.Confirmation{}
+		if data.Request.TLS != nil && len(data.Request.TLS.PeerCertificates) > 0 {
+			cnf.X5tS256 = util.CertificateThumbprint(data.Request.TLS.PeerCertificates[0])
+		}
+		if proof := domain.DPoPProofFromContext(data.Request.Context()); proof != nil {
+			cnf.JKT = proof.JKT
+		}
+		if cnf != (domain.Confirmation{}) {
+			claims.Cnf = &cnf
+		}
+	}
+	access, err = signAccessToken(key, claims)
 	if err != nil {
 		return "", "", err
 	}
 
-	// Generate refresh token (optional)
+	// Generate a refresh token that stays in the family of the one being rotated
 	if isGenRefresh {
-		refresh = uuid.New().String()
+		family := uuid.New().String()
+		if data.Request != nil && oauth2.GrantType(data.Request.FormValue("grant_type")) == oauth2.Refreshing {
+			family = domain.RefreshTokenFamily(data.Request.FormValue("refresh_token"))
+		}
+		secret, err := util.RandomToken(32)
+		if err != nil {
+			return "", "", err
+		}
+		refresh = domain.NewRefreshToken(family, secret)
 	}
 
 	return
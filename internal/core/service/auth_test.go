@@ -0,0 +1,75 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gopkg.in/oauth2.v3/errors"
+)
+
+func newTokenRequest(form url.Values) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/connect/token", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func TestClientInfoHandler(t *testing.T) {
+	repo := newFakeRepository()
+	addTestClients(t, repo)
+	store := newClientStotreService(repo, newTestService(repo), nil)
+
+	tests := []struct {
+		name string
+		form url.Values
+		want error
+	}{
+		{"public client with authorization code", url.Values{"grant_type": {"authorization_code"}, "client_id": {"public"}}, nil},
+		{"public client refreshing", url.Values{"grant_type": {"refresh_token"}, "client_id": {"public"}}, nil},
+		{"public client with client credentials", url.Values{"grant_type": {"client_credentials"}, "client_id": {"public"}}, errors.ErrInvalidClient},
+		{"public client with secret", url.Values{"grant_type": {"authorization_code"}, "client_id": {"public"}, "client_secret": {"anything"}}, errors.ErrInvalidClient},
+		{"confidential client with secret", url.Values{"grant_type": {"client_credentials"}, "client_id": {"confidential"}, "client_secret": {"s3cret"}}, nil},
+		{"confidential client with wrong secret", url.Values{"grant_type": {"client_credentials"}, "client_id": {"confidential"}, "client_secret": {"wrong"}}, errors.ErrInvalidClient},
+		{"confidential client without secret", url.Values{"grant_type": {"authorization_code"}, "client_id": {"confidential"}}, errors.ErrInvalidClient},
+		{"disabled client", url.Values{"grant_type": {"authorization_code"}, "client_id": {"disabled"}}, errors.ErrInvalidClient},
+		{"unknown client", url.Values{"grant_type": {"authorization_code"}, "client_id": {"unknown"}}, errors.ErrInvalidClient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientID, _, err := store.ClientInfoHandler(newTokenRequest(tt.form))
+			if err != tt.want {
+				t.Fatalf("ClientInfoHandler() error = %v, want %v", err, tt.want)
+			}
+			if err == nil && clientID != tt.form.Get("client_id") {
+				t.Errorf("ClientInfoHandler() client id = %q, want %q", clientID, tt.form.Get("client_id"))
+			}
+		})
+	}
+}
+
+func TestRefreshingScopeHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		newScope string
+		want     bool
+	}{
+		{"same scope", "openid offline_access api", true},
+		{"narrower scope", "api", true},
+		{"reordered scope", "api openid", true},
+		{"wider scope", "openid api admin", false},
+		{"other scope", "admin", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := refreshingScopeHandler(tt.newScope, "openid offline_access api")
+			if err != nil {
+				t.Fatalf("refreshingScopeHandler() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("refreshingScopeHandler() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
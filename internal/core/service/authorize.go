@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
+	"gopkg.in/oauth2.v3"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+// GetAuthorizeClient returns an enabled client when the redirect uri is registered for it
+func (s *Service) GetAuthorizeClient(ctx context.Context, clientID, redirectURI string) (*domain.Client, error) {
+	logrus.Info("package service GetAuthorizeClient() authorize function called.")
+	if clientID == "" || redirectURI == "" {
+		return nil, errors.ErrInvalidRequest
+	}
+	client, err := s.repo.Client().GetDetailByClientID(ctx, clientID)
+	if err != nil {
+		return nil, errors.ErrInvalidClient
+	}
+	if !client.Enabled {
+		return nil, errors.ErrUnauthorizedClient
+	}
+	if !client.HasRedirectUri(redirectURI) {
+		return nil, errors.ErrInvalidRedirectURI
+	}
+	return client, nil
+}
+
+// ValidateAuthorizeRequest checks the request against the client registration and enforces PKCE
+func (s *Service) ValidateAuthorizeRequest(ctx context.Context, client *domain.Client, req *domain.AuthorizeRequest) error {
+	if oauth2.ResponseType(req.ResponseType) != oauth2.Code {
+		return errors.ErrUnsupportedResponseType
+	}
+	if !client.HasGrantType(oauth2.AuthorizationCode.String()) {
+		return errors.ErrUnauthorizedClient
+	}
+	if !client.HasScopes(req.Scope) {
+		return errors.ErrInvalidScope
+	}
+	if err := s.ValidateScopes(ctx, req.Scope); err != nil {
+		return err
+	}
+	if req.CodeChallenge == "" {
+		if client.IsPublic() {
+			return errors.ErrInvalidRequest
+		}
+		return nil
+	}
+	if req.CodeChallengeMethod != util.CodeChallengeS256 {
+		return errors.ErrInvalidRequest
+	}
+	return nil
+}
+
+// SaveAuthorizationCode persists the request context of an authorization code until it expires
+func (s *Service) SaveAuthorizationCode(ctx context.Context, code string, data *domain.AuthorizationCode, expiration time.Time) error {
+	_, err := s.repo.PersistedGrant().Create(ctx, &domain.PersistedGrant{
+		Key:          domain.GrantKey(code, domain.GrantTypeAuthorizationCode),
+		Type:         domain.GrantTypeAuthorizationCode,
+		SubjectID:    data.SubjectID,
+		ClientID:     data.ClientID,
+		CreationTime: time.Now(),
+		Expiration:   expiration,
+		Data:         string(domain.ConvertToJson(data)),
+	})
+	return err
+}
+
+// VerifyAuthorizationCode checks the client and the PKCE code verifier of a code and then consumes its
+// stored context, so a request from another client or with a wrong verifier cannot burn the code
+func (s *Service) VerifyAuthorizationCode(ctx context.Context, code, clientID, codeVerifier string) (*domain.AuthorizationCode, error) {
+	key := domain.GrantKey(code, domain.GrantTypeAuthorizationCode)
+	grant, err := s.repo.PersistedGrant().Get(ctx, key)
+	if err != nil {
+		return nil, errors.ErrInvalidGrant
+	}
+	if grant.ClientID != clientID || grant.Expiration.Before(time.Now()) {
+		return nil, errors.ErrInvalidGrant
+	}
+	data := domain.ConvertFromJson[domain.AuthorizationCode]([]byte(grant.Data))
+	if data.CodeChallenge == "" && codeVerifier != "" {
+		return nil, errors.ErrInvalidGrant
+	}
+	if data.CodeChallenge != "" && !util.VerifyCodeChallenge(codeVerifier, data.CodeChallenge) {
+		return nil, errors.ErrInvalidGrant
+	}
+	err = s.repo.PersistedGrant().Redeem(ctx, key)
+	if err == domain.ErrDataNotFound {
+		return nil, errors.ErrInvalidGrant
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// pushedRequestLifetime is how long a request_uri can be used at the authorization endpoint
+const pushedRequestLifetime = 60 * time.Second
+
+// PushAuthorizationRequest validates the request like the authorization endpoint would and
+// stores it behind a request_uri, see RFC 9126 section 2
+func (s *Service) PushAuthorizationRequest(ctx context.Context, req *domain.AuthorizeRequest) (*domain.PushedAuthorizationResponse, error) {
+	logrus.Info("package service PushAuthorizationRequest() authorize function called.")
+	if req.RequestURI != "" {
+		return nil, errors.ErrInvalidRequest
+	}
+	client, err := s.GetAuthorizeClient(ctx, req.ClientID, req.RedirectURI)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ValidateAuthorizeRequest(ctx, client, req); err != nil {
+		return nil, err
+	}
+	handle, err := util.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	data := *req
+	data.Email, data.Password = "", ""
+	data.Consent, data.ConsentScopes = "", nil
+	now := time.Now()
+	requestURI := domain.RequestURIPrefix + handle
+	_, err = s.repo.PersistedGrant().Create(ctx, &domain.PersistedGrant{
+		Key:          domain.GrantKey(requestURI, domain.GrantTypePushedRequest),
+		Type:         domain.GrantTypePushedRequest,
+		ClientID:     req.ClientID,
+		CreationTime: now,
+		Expiration:   now.Add(pushedRequestLifetime),
+		Data:         string(domain.ConvertToJson(data)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.PushedAuthorizationResponse{
+		RequestURI: requestURI,
+		ExpiresIn:  int(pushedRequestLifetime.Seconds()),
+	}, nil
+}
+
+// GetPushedAuthorizationRequest returns the unexpired request pushed by the client behind the request_uri
+func (s *Service) GetPushedAuthorizationRequest(ctx context.Context, clientID, requestURI string) (*domain.AuthorizeRequest, error) {
+	logrus.Info("package service GetPushedAuthorizationRequest() authorize function called.")
+	grant, err := s.repo.PersistedGrant().Get(ctx, domain.GrantKey(requestURI, domain.GrantTypePushedRequest))
+	if err != nil {
+		return nil, errors.ErrInvalidRequest
+	}
+	if grant.ClientID != clientID || grant.Expiration.Before(time.Now()) {
+		return nil, errors.ErrInvalidRequest
+	}
+	data := domain.ConvertFromJson[domain.AuthorizeRequest]([]byte(grant.Data))
+	data.RequestURI = requestURI
+	return &data, nil
+}
+
+// DeletePushedAuthorizationRequest removes a request_uri so it cannot be used for another code
+func (s *Service) DeletePushedAuthorizationRequest(ctx context.Context, requestURI string) error {
+	return s.repo.PersistedGrant().Delete(ctx, domain.GrantKey(requestURI, domain.GrantTypePushedRequest))
+}
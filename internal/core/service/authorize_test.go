@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sugaml/authserver/internal/core/domain"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+// Example of RFC 7636 appendix B
+const (
+	testCodeVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	testCodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+)
+
+func saveTestCode(t *testing.T, svc *Service, code, challenge string, expiration time.Time) {
+	t.Helper()
+	err := svc.SaveAuthorizationCode(context.Background(), code, &domain.AuthorizationCode{
+		ClientID:            "public",
+		SubjectID:           "1",
+		CodeChallenge:       challenge,
+		CodeChallengeMethod: "S256",
+	}, expiration)
+	if err != nil {
+		t.Fatalf("SaveAuthorizationCode() error = %v", err)
+	}
+}
+
+func TestVerifyAuthorizationCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		challenge string
+		clientID  string
+		verifier  string
+		expired   bool
+		want      error
+	}{
+		{"matching verifier", testCodeChallenge, "public", testCodeVerifier, false, nil},
+		{"wrong verifier", testCodeChallenge, "public", testCodeVerifier + "x", false, errors.ErrInvalidGrant},
+		{"missing verifier", testCodeChallenge, "public", "", false, errors.ErrInvalidGrant},
+		{"verifier without challenge", "", "public", testCodeVerifier, false, errors.ErrInvalidGrant},
+		{"no challenge", "", "public", "", false, nil},
+		{"other client", testCodeChallenge, "other", testCodeVerifier, false, errors.ErrInvalidGrant},
+		{"expired code", testCodeChallenge, "public", testCodeVerifier, true, errors.ErrInvalidGrant},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService(newFakeRepository())
+			expiration := time.Now().Add(time.Minute)
+			if tt.expired {
+				expiration = time.Now().Add(-time.Minute)
+			}
+			saveTestCode(t, svc, "code", tt.challenge, expiration)
+			data, err := svc.VerifyAuthorizationCode(context.Background(), "code", tt.clientID, tt.verifier)
+			if err != tt.want {
+				t.Fatalf("VerifyAuthorizationCode() error = %v, want %v", err, tt.want)
+			}
+			if err == nil && data.SubjectID != "1" {
+				t.Errorf("VerifyAuthorizationCode() subject = %q, want %q", data.SubjectID, "1")
+			}
+		})
+	}
+}
+
+func TestVerifyAuthorizationCodeSingleUse(t *testing.T) {
+	svc := newTestService(newFakeRepository())
+	saveTestCode(t, svc, "code", testCodeChallenge, time.Now().Add(time.Minute))
+	if _, err := svc.VerifyAuthorizationCode(context.Background(), "code", "public", testCodeVerifier); err != nil {
+		t.Fatalf("VerifyAuthorizationCode() error = %v", err)
+	}
+	if _, err := svc.VerifyAuthorizationCode(context.Background(), "code", "public", testCodeVerifier); err != errors.ErrInvalidGrant {
+		t.Errorf("VerifyAuthorizationCode() second use error = %v, want %v", err, errors.ErrInvalidGrant)
+	}
+}
+
+func TestVerifyAuthorizationCodeKeptOnFailure(t *testing.T) {
+	svc := newTestService(newFakeRepository())
+	saveTestCode(t, svc, "code", testCodeChallenge, time.Now().Add(time.Minute))
+	if _, err := svc.VerifyAuthorizationCode(context.Background(), "code", "public", "wrong"); err != errors.ErrInvalidGrant {
+		t.Fatalf("VerifyAuthorizationCode() error = %v, want %v", err, errors.ErrInvalidGrant)
+	}
+	if _, err := svc.VerifyAuthorizationCode(context.Background(), "code", "other", testCodeVerifier); err != errors.ErrInvalidGrant {
+		t.Fatalf("VerifyAuthorizationCode() other client error = %v, want %v", err, errors.ErrInvalidGrant)
+	}
+	if _, err := svc.VerifyAuthorizationCode(context.Background(), "code", "public", testCodeVerifier); err != nil {
+		t.Errorf("VerifyAuthorizationCode() after failed attempts error = %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"crypto/subtle"
+	"crypto/x509"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
+)
+
+// verifyClientCertificate matches the TLS client certificate against the X509Name secrets of
+// the client once its chain verifies against the client CAs, or against the X509Thumbprint
+// secrets of a self-signed certificate, see RFC 8705 section 2
+func (s *Service) verifyClientCertificate(client *domain.Client, certs []*x509.Certificate) bool {
+	if len(certs) == 0 {
+		return false
+	}
+	thumbprint := util.CertificateThumbprint(certs[0])
+	for _, cs := range client.ClientSecrets {
+		if cs.Expiration != nil && cs.Expiration.Before(time.Now()) {
+			continue
+		}
+		switch cs.Type {
+		case domain.SecretTypeX509Thumbprint:
+			if subtle.ConstantTimeCompare([]byte(cs.Value), []byte(thumbprint)) == 1 {
+				return true
+			}
+		case domain.SecretTypeX509Name:
+			if cs.Value == certs[0].Subject.String() && s.verifyCertificateChain(certs) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verifyCertificateChain verifies a client certificate and its intermediates against the client CAs
+func (s *Service) verifyCertificateChain(certs []*x509.Certificate) bool {
+	if s.config.ClientCAFile == "" {
+		return false
+	}
+	roots, err := util.LoadCertPool(s.config.ClientCAFile)
+	if err != nil {
+		logrus.Error("package service verifyCertificateChain() client CA file: ", err)
+		return false
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return err == nil
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
+)
+
+// idTokenLifetime is how long an id_token is valid after it is issued
+const idTokenLifetime = 5 * time.Minute
+
+// reservedClaims can never be overridden by user claims
+var reservedClaims = map[string]bool{
+	"iss": true, "sub": true, "aud": true, "exp": true, "iat": true, "nbf": true, "jti": true,
+	"azp": true, "nonce": true, "at_hash": true, "c_hash": true, "auth_time": true, "acr": true, "amr": true, "sid": true,
+}
+
+// CreateIDToken signs an id_token for the subject with the identity claims of the requested scopes
+func (s *Service) CreateIDToken(ctx context.Context, req *domain.IDTokenRequest) (string, error) {
+	logrus.Info("package service CreateIDToken() claims function called.")
+	claims, err := s.resolveUserClaims(ctx, req.SubjectID, req.Scope)
+	if err != nil {
+		return "", err
+	}
+	key, err := s.GetSigningKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims["iss"] = s.config.Issuer
+	claims["aud"] = req.ClientID
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(idTokenLifetime).Unix()
+	if !req.AuthTime.IsZero() {
+		claims["auth_time"] = req.AuthTime.Unix()
+	}
+	if req.Nonce != "" {
+		claims["nonce"] = req.Nonce
+	}
+	if req.Sid != "" {
+		claims["sid"] = req.Sid
+	}
+	if req.AccessToken != "" {
+		claims["at_hash"] = util.TokenHash(req.AccessToken, key.Algorithm)
+	}
+	return signToken(key, claims)
+}
+
+// GetUserInfo returns the user claims released by the scopes of an access token
+func (s *Service) GetUserInfo(ctx context.Context, subjectID, scope string) (domain.Map, error) {
+	logrus.Info("package service GetUserInfo() claims function called.")
+	claims, err := s.resolveUserClaims(ctx, subjectID, scope)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Map(claims), nil
+}
+
+// resolveUserClaims returns sub plus the user claims that the identity resources of the scopes map to
+func (s *Service) resolveUserClaims(ctx context.Context, subjectID, scope string) (jwt.MapClaims, error) {
+	id, err := strconv.ParseUint(subjectID, 10, 64)
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+	user, err := s.repo.User().GetWithClaims(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := s.repo.IdentityResource().ListByNames(ctx, strings.Fields(scope))
+	if err != nil {
+		return nil, err
+	}
+	allowed := map[string]bool{}
+	for _, resource := range resources {
+		for _, claim := range resource.IdentityClaims {
+			allowed[claim.Type] = true
+		}
+	}
+
+	values := map[string]interface{}{
+		"email":                 user.Email,
+		"email_verified":        user.EmailConfirmed,
+		"phone_number_verified": user.PhoneNumberConfirmed,
+		"updated_at":            user.UpdatedAt.Unix(),
+	}
+	if user.UserName != "" {
+		values["preferred_username"] = user.UserName
+	}
+	grouped := map[string][]interface{}{}
+	for _, claim := range user.UserClaims {
+		grouped[claim.ClaimType] = append(grouped[claim.ClaimType], claimValue(claim.ClaimType, claim.ClaimValue))
+	}
+	for claimType, claimValues := range grouped {
+		if len(claimValues) == 1 {
+			values[claimType] = claimValues[0]
+			continue
+		}
+		values[claimType] = claimValues
+	}
+
+	claims := jwt.MapClaims{"sub": subjectID}
+	for claimType, value := range values {
+		if allowed[claimType] && !reservedClaims[claimType] {
+			claims[claimType] = value
+		}
+	}
+	return claims, nil
+}
+
+// claimValue converts the string stored in UserClaim into the JSON type of standard claims
+func claimValue(claimType, value string) interface{} {
+	switch claimType {
+	case "email_verified", "phone_number_verified":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case "updated_at":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "address":
+		var address map[string]interface{}
+		if err := json.Unmarshal([]byte(value), &address); err == nil {
+			return address
+		}
+	}
+	return value
+}
+
+// signToken signs the claims with the key and sets its kid header
+func signToken(key *domain.SigningKey, claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.PrivateKey)
+}
+
+// signAccessToken signs an access token with the at+jwt type, so it cannot be mistaken for an
+// id_token, see RFC 9068 section 2.1
+func signAccessToken(key *domain.SigningKey, claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+	token.Header["typ"] = domain.AccessTokenType
+	return token.SignedString(key.PrivateKey)
+}
@@ -12,6 +12,9 @@ import (
 // Create a new client
 func (s *Service) CreateClient(ctx context.Context, req *domain.ClientRequest) (*domain.ClientResponse, error) {
 	logrus.Info("package service Create() client function called.")
+	if err := req.ClientPropertiesRequest.Validate(); err != nil {
+		return nil, err
+	}
 	data := domain.Convert[domain.ClientRequest, domain.Client](req)
 	data.New(req)
 	err := data.Validate()
@@ -62,8 +65,11 @@ func (s *Service) ListClient(ctx context.Context, req *domain.ClientListRequest)
 	return datas, count, nil
 }
 
-// Update updates a client
+// Update updates a client and replaces the client properties the request sets
 func (cs *Service) UpdateClient(ctx context.Context, id string, req *domain.ClientUpdateRequest) (*domain.ClientResponse, error) {
+	if err := req.ClientPropertiesRequest.Validate(); err != nil {
+		return nil, err
+	}
 	_, err := cs.repo.Client().Get(ctx, id)
 	if err != nil {
 		if err == domain.ErrDataNotFound {
@@ -71,6 +77,11 @@ func (cs *Service) UpdateClient(ctx context.Context, id string, req *domain.Clie
 		}
 		return nil, domain.ErrInternal
 	}
+	if props := req.Properties(); len(props) > 0 {
+		if err := cs.repo.Client().UpdateProperties(ctx, id, props); err != nil {
+			return nil, domain.ErrInternal
+		}
+	}
 	mp := req.NewUpdate()
 	result, err := cs.repo.Client().Update(ctx, id, mp)
 	if err != nil {
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+// withdrawnGrantTypes are removed together with a user consent, so the client loses access at once
+var withdrawnGrantTypes = []string{domain.GrantTypeUserConsent, domain.GrantTypeReferenceToken, domain.GrantTypeRefreshToken}
+
+// GetConsent describes the requested scopes for the consent page, or returns nil when the user
+// already consented to every one of them
+func (s *Service) GetConsent(ctx context.Context, subjectID string, client *domain.Client, scope string) (*domain.Consent, error) {
+	logrus.Info("package service GetConsent() consent function called.")
+	requested := strings.Fields(scope)
+	granted, err := s.consentedScopes(ctx, subjectID, client.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	pending := false
+	for _, name := range requested {
+		if !granted[name] {
+			pending = true
+		}
+	}
+	if !pending {
+		return nil, nil
+	}
+	identities, err := s.repo.IdentityResource().ListByNames(ctx, requested)
+	if err != nil {
+		return nil, err
+	}
+	identityScopes := map[string]*domain.IdentityResource{}
+	for _, identity := range identities {
+		identityScopes[identity.Name] = identity
+	}
+	apis, err := s.repo.ApiScope().ListByNames(ctx, requested)
+	if err != nil {
+		return nil, err
+	}
+	apiScopes := map[string]*domain.ApiScope{}
+	for _, api := range apis {
+		apiScopes[api.Name] = api
+	}
+	consent := &domain.Consent{}
+	for _, name := range requested {
+		if identity, ok := identityScopes[name]; ok {
+			required := identity.Required || name == domain.ScopeOpenID
+			consent.IdentityScopes = append(consent.IdentityScopes, domain.ConsentScope{
+				Name:        name,
+				DisplayName: displayName(identity.DisplayName, name),
+				Description: identity.Description,
+				Required:    required,
+				Emphasize:   identity.Emphasize,
+				Checked:     required || granted[name],
+			})
+			continue
+		}
+		if api, ok := apiScopes[name]; ok {
+			consent.ApiScopes = append(consent.ApiScopes, domain.ConsentScope{
+				Name:        name,
+				DisplayName: displayName(api.DisplayName, name),
+				Description: api.Description,
+				Required:    api.Required,
+				Emphasize:   api.Emphasize,
+				Checked:     api.Required || granted[name],
+			})
+			continue
+		}
+		// Scopes without a resource, such as offline_access
+		consent.ApiScopes = append(consent.ApiScopes, domain.ConsentScope{Name: name, DisplayName: name, Checked: granted[name]})
+	}
+	return consent, nil
+}
+
+// SaveConsent stores the scopes the user consented to and returns the requested scopes that may be
+// granted, which are the required scopes and those the user checked
+func (s *Service) SaveConsent(ctx context.Context, subjectID string, client *domain.Client, scope string, consented []string) (string, error) {
+	logrus.Info("package service SaveConsent() consent function called.")
+	consent, err := s.GetConsent(ctx, subjectID, client, scope)
+	if err != nil {
+		return "", err
+	}
+	allowed := map[string]bool{}
+	for _, name := range consented {
+		allowed[name] = true
+	}
+	if consent != nil {
+		for _, item := range append(consent.IdentityScopes, consent.ApiScopes...) {
+			if item.Required {
+				allowed[item.Name] = true
+			}
+		}
+	}
+	granted, err := s.consentedScopes(ctx, subjectID, client.ClientID)
+	if err != nil {
+		return "", err
+	}
+	requested := strings.Fields(scope)
+	var scopes []string
+	for _, name := range requested {
+		delete(granted, name)
+		if allowed[name] {
+			scopes = append(scopes, name)
+		}
+	}
+	if len(scopes) == 0 {
+		return "", errors.ErrAccessDenied
+	}
+	// Scopes consented to for other requests are kept
+	data := domain.UserConsent{Scopes: scopes}
+	for name := range granted {
+		data.Scopes = append(data.Scopes, name)
+	}
+	key := domain.GrantKey(subjectID+":"+client.ClientID, domain.GrantTypeUserConsent)
+	if err := s.repo.PersistedGrant().Delete(ctx, key); err != nil {
+		return "", err
+	}
+	_, err = s.repo.PersistedGrant().Create(ctx, &domain.PersistedGrant{
+		Key:          key,
+		Type:         domain.GrantTypeUserConsent,
+		SubjectID:    subjectID,
+		ClientID:     client.ClientID,
+		CreationTime: time.Now(),
+		Data:         string(domain.ConvertToJson(data)),
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(scopes, " "), nil
+}
+
+// ListConsents returns the clients the user consented to
+func (s *Service) ListConsents(ctx context.Context, subjectID string) ([]*domain.UserConsentResponse, error) {
+	logrus.Info("package service ListConsents() consent function called.")
+	grants, err := s.repo.PersistedGrant().ListBySubject(ctx, subjectID, domain.GrantTypeUserConsent)
+	if err != nil {
+		return nil, err
+	}
+	datas := []*domain.UserConsentResponse{}
+	for _, grant := range grants {
+		data := domain.ConvertFromJson[domain.UserConsent]([]byte(grant.Data))
+		result := &domain.UserConsentResponse{
+			ClientID:  grant.ClientID,
+			Scopes:    data.Scopes,
+			CreatedAt: grant.CreationTime,
+		}
+		if client, err := s.repo.Client().GetCliendID(ctx, grant.ClientID); err == nil {
+			result.ClientName = client.ClientName
+		}
+		datas = append(datas, result)
+	}
+	return datas, nil
+}
+
+// WithdrawConsent removes the consent of the user for the client together with the access and
+// refresh tokens issued to the client for the user
+func (s *Service) WithdrawConsent(ctx context.Context, subjectID, clientID string) error {
+	logrus.Info("package service WithdrawConsent() consent function called.")
+	_, err := s.repo.PersistedGrant().Get(ctx, domain.GrantKey(subjectID+":"+clientID, domain.GrantTypeUserConsent))
+	if err != nil {
+		return domain.ErrDataNotFound
+	}
+	return s.repo.PersistedGrant().DeleteBySubject(ctx, subjectID, clientID, withdrawnGrantTypes)
+}
+
+// consentedScopes returns the scopes the user consented to for the client
+func (s *Service) consentedScopes(ctx context.Context, subjectID, clientID string) (map[string]bool, error) {
+	scopes := map[string]bool{}
+	grant, err := s.repo.PersistedGrant().Get(ctx, domain.GrantKey(subjectID+":"+clientID, domain.GrantTypeUserConsent))
+	if err == domain.ErrDataNotFound {
+		return scopes, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data := domain.ConvertFromJson[domain.UserConsent]([]byte(grant.Data))
+	for _, name := range data.Scopes {
+		scopes[name] = true
+	}
+	return scopes, nil
+}
+
+// displayName falls back to the scope name when no display name is configured
+func displayName(name, scope string) string {
+	if name == "" {
+		return scope
+	}
+	return name
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+// Device authorization lifetimes, see RFC 8628 section 3.2
+const (
+	deviceCodeLifetime = 5 * time.Minute
+	devicePollInterval = 5
+	userCodeLength     = 8
+)
+
+// Device grant errors, see RFC 8628 section 3.5
+var (
+	errAuthorizationPending = stderrors.New("authorization_pending")
+	errSlowDown             = stderrors.New("slow_down")
+	errExpiredDeviceCode    = stderrors.New("expired_token")
+)
+
+func init() {
+	errors.Descriptions[errAuthorizationPending] = "The authorization request is still pending as the end user hasn't yet completed the user interaction steps"
+	errors.Descriptions[errSlowDown] = "The authorization request is still pending and polling should continue, but the interval must be increased by 5 seconds"
+	errors.Descriptions[errExpiredDeviceCode] = "The device code has expired and the device authorization session has concluded"
+	errors.StatusCodes[errAuthorizationPending] = 400
+	errors.StatusCodes[errSlowDown] = 400
+	errors.StatusCodes[errExpiredDeviceCode] = 400
+}
+
+// CreateDeviceAuthorization issues a device code and a user code for an enabled client
+func (s *Service) CreateDeviceAuthorization(ctx context.Context, clientID, scope string) (*domain.DeviceAuthorizationResponse, error) {
+	logrus.Info("package service CreateDeviceAuthorization() device function called.")
+	client, err := s.repo.Client().GetDetailByClientID(ctx, clientID)
+	if err != nil {
+		return nil, errors.ErrInvalidClient
+	}
+	if !client.Enabled || !client.HasGrantType(domain.GrantTypeDeviceCode) {
+		return nil, errors.ErrUnauthorizedClient
+	}
+	if !client.HasScopes(scope) {
+		return nil, errors.ErrInvalidScope
+	}
+	if err := s.ValidateScopes(ctx, scope); err != nil {
+		return nil, err
+	}
+	deviceCode, err := util.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	userCode, err := util.RandomUserCode(userCodeLength)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	data := &domain.DeviceAuthorization{
+		ClientID: clientID,
+		Scope:    scope,
+		Interval: devicePollInterval,
+	}
+	_, err = s.repo.DeviceCode().Create(ctx, &domain.DeviceCode{
+		UserCode:     userCode,
+		DeviceCode:   domain.GrantKey(deviceCode, domain.GrantTypeDeviceCode),
+		ClientID:     clientID,
+		CreationTime: now,
+		Expiration:   now.Add(deviceCodeLifetime),
+		Data:         string(domain.ConvertToJson(data)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.DeviceAuthorizationResponse{
+		DeviceCode: deviceCode,
+		UserCode:   userCode[:userCodeLength/2] + "-" + userCode[userCodeLength/2:],
+		ExpiresIn:  int64(deviceCodeLifetime.Seconds()),
+		Interval:   devicePollInterval,
+	}, nil
+}
+
+// GetDeviceAuthorization returns the pending request of an unexpired user code
+func (s *Service) GetDeviceAuthorization(ctx context.Context, userCode string) (*domain.Client, *domain.DeviceAuthorization, error) {
+	result, err := s.repo.DeviceCode().GetByUserCode(ctx, normalizeUserCode(userCode))
+	if err != nil || result.Expiration.Before(time.Now()) || result.SubjectID != "" {
+		return nil, nil, domain.ErrDataNotFound
+	}
+	data := domain.ConvertFromJson[domain.DeviceAuthorization]([]byte(result.Data))
+	if data.Denied {
+		return nil, nil, domain.ErrDataNotFound
+	}
+	client, err := s.repo.Client().GetCliendID(ctx, result.ClientID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, &data, nil
+}
+
+// CompleteDeviceAuthorization approves the user code for the subject or denies it
+func (s *Service) CompleteDeviceAuthorization(ctx context.Context, userCode, subjectID string, approved bool) error {
+	logrus.Info("package service CompleteDeviceAuthorization() device function called.")
+	userCode = normalizeUserCode(userCode)
+	_, data, err := s.GetDeviceAuthorization(ctx, userCode)
+	if err != nil {
+		return err
+	}
+	update := domain.Map{}
+	if approved {
+		data.SubjectID = subjectID
+		data.AuthTime = time.Now()
+		update["subject_id"] = subjectID
+	} else {
+		data.Denied = true
+	}
+	update["data"] = string(domain.ConvertToJson(data))
+	return s.repo.DeviceCode().Update(ctx, userCode, update)
+}
+
+// PollDeviceCode consumes an approved device code. Until then it reports authorization_pending,
+// or slow_down when the client polls faster than the interval, which then grows by 5 seconds.
+// The client is checked again on every poll, as it may have been disabled or changed meanwhile.
+func (s *Service) PollDeviceCode(ctx context.Context, clientID, deviceCode string) (*domain.DeviceAuthorization, error) {
+	result, err := s.repo.DeviceCode().GetByDeviceCode(ctx, domain.GrantKey(deviceCode, domain.GrantTypeDeviceCode))
+	if err != nil || result.ClientID != clientID {
+		return nil, errors.ErrInvalidGrant
+	}
+	data := domain.ConvertFromJson[domain.DeviceAuthorization]([]byte(result.Data))
+	client, err := s.repo.Client().GetDetailByClientID(ctx, clientID)
+	if err != nil {
+		return nil, errors.ErrInvalidClient
+	}
+	if !client.Enabled || !client.HasGrantType(domain.GrantTypeDeviceCode) {
+		return nil, errors.ErrUnauthorizedClient
+	}
+	if !client.HasScopes(data.Scope) {
+		return nil, errors.ErrInvalidScope
+	}
+	if err := s.ValidateScopes(ctx, data.Scope); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if result.Expiration.Before(now) || data.Denied {
+		if err := s.repo.DeviceCode().Delete(ctx, result.UserCode); err != nil {
+			return nil, err
+		}
+		if data.Denied {
+			return nil, errors.ErrAccessDenied
+		}
+		return nil, errExpiredDeviceCode
+	}
+	if result.SubjectID != "" {
+		err := s.repo.DeviceCode().Redeem(ctx, result.UserCode)
+		if err == domain.ErrDataNotFound {
+			return nil, errors.ErrInvalidGrant
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &data, nil
+	}
+	pollErr := errAuthorizationPending
+	if !data.LastPolled.IsZero() && now.Sub(data.LastPolled) < time.Duration(data.Interval)*time.Second {
+		data.Interval += devicePollInterval
+		pollErr = errSlowDown
+	}
+	data.LastPolled = now
+	err = s.repo.DeviceCode().Update(ctx, result.UserCode, domain.Map{"data": string(domain.ConvertToJson(data))})
+	if err != nil {
+		return nil, err
+	}
+	return nil, pollErr
+}
+
+// normalizeUserCode strips the separator and case a user may type the code with
+func normalizeUserCode(userCode string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(userCode))
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ListDiscoveryScopes returns the identity and api scopes flagged for the discovery document,
+// together with the claims of the identity scopes
+func (s *Service) ListDiscoveryScopes(ctx context.Context) ([]string, []string, error) {
+	logrus.Info("package service ListDiscoveryScopes() discovery function called.")
+	scopes := []string{}
+	claims := []string{}
+	seen := map[string]bool{}
+	identities, err := s.repo.IdentityResource().ListDiscoverable(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, identity := range identities {
+		scopes = append(scopes, identity.Name)
+		for _, claim := range identity.IdentityClaims {
+			if !seen[claim.Type] {
+				seen[claim.Type] = true
+				claims = append(claims, claim.Type)
+			}
+		}
+	}
+	apiScopes, err := s.repo.ApiScope().ListDiscoverable(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, apiScope := range apiScopes {
+		scopes = append(scopes, apiScope.Name)
+	}
+	return scopes, claims, nil
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+// errInvalidDPoPProof is returned for a malformed, mismatching or replayed DPoP proof, see RFC 9449 section 5
+var errInvalidDPoPProof = stderrors.New("invalid_dpop_proof")
+
+func init() {
+	errors.Descriptions[errInvalidDPoPProof] = "The DPoP proof is invalid"
+	errors.StatusCodes[errInvalidDPoPProof] = 400
+}
+
+// VerifyDPoPProof checks a DPoP proof for the request and remembers its jti until the proof
+// expires, so it cannot be replayed
+func (s *Service) VerifyDPoPProof(ctx context.Context, proof, method, uri, accessToken string) (*domain.DPoPProof, error) {
+	logrus.Info("package service VerifyDPoPProof() dpop function called.")
+	result, err := util.VerifyDPoPProof(proof, method, uri, accessToken)
+	if err != nil {
+		return nil, errInvalidDPoPProof
+	}
+	// The thumbprint and jti make the primary key of the grant, so a replayed proof fails to insert
+	_, err = s.repo.PersistedGrant().Create(ctx, &domain.PersistedGrant{
+		Key:          domain.GrantKey(result.JKT+":"+result.JTI, domain.GrantTypeDPoPProof),
+		Type:         domain.GrantTypeDPoPProof,
+		CreationTime: time.Now(),
+		Expiration:   result.IssuedAt.Add(util.DPoPProofLifetime),
+	})
+	if err != nil {
+		return nil, errInvalidDPoPProof
+	}
+	return result, nil
+}
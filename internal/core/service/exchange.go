@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"gopkg.in/oauth2.v3/errors"
+	"gopkg.in/oauth2.v3/manage"
+)
+
+// errInvalidTarget is returned for an audience outside the exchange policy, see RFC 8693 section 2.2.2,
+// and for a resource indicator that is unknown or not covered by the granted scopes, see RFC 8707 section 2
+var errInvalidTarget = stderrors.New("invalid_target")
+
+func init() {
+	errors.Descriptions[errInvalidTarget] = "The requested audience or resource is invalid or not allowed"
+	errors.StatusCodes[errInvalidTarget] = 400
+}
+
+// ExchangeToken applies the audience policy of the client and narrows the scope of the subject token.
+// The subject token must be meant for the client and, when bound to a key, presented with that key.
+// The issued token never outlives the subject token and keeps the delegation chain of its act claim.
+func (s *Service) ExchangeToken(ctx context.Context, req *domain.TokenExchangeRequest) (*domain.TokenExchange, error) {
+	logrus.Info("package service ExchangeToken() exchange function called.")
+	client, err := s.repo.Client().GetDetailByClientID(ctx, req.ClientID)
+	// Only a confidential client may exchange tokens, as a public one is not authenticated
+	if err != nil || client.IsPublic() {
+		return nil, errors.ErrInvalidClient
+	}
+	if !client.Enabled || !client.HasGrantType(domain.GrantTypeTokenExchange) {
+		return nil, errors.ErrUnauthorizedClient
+	}
+	if req.Audience == "" {
+		return nil, errors.ErrInvalidRequest
+	}
+	if !req.SubjectAudience.Contains(req.ClientID) {
+		return nil, errors.ErrInvalidGrant
+	}
+	if cnf := req.SubjectCnf; cnf != nil {
+		if cnf.X5tS256 != "" && cnf.X5tS256 != req.Confirmation.X5tS256 {
+			return nil, errors.ErrInvalidGrant
+		}
+		if cnf.JKT != "" && cnf.JKT != req.Confirmation.JKT {
+			return nil, errors.ErrInvalidGrant
+		}
+	}
+	if !client.HasExchangeAudience(req.Audience) {
+		return nil, errInvalidTarget
+	}
+	var scopes []string
+	if req.Scope == "" {
+		for _, sc := range strings.Fields(req.SubjectScope) {
+			if client.HasScopes(sc) {
+				scopes = append(scopes, sc)
+			}
+		}
+	} else {
+		for _, sc := range strings.Fields(req.Scope) {
+			if !domain.HasScope(req.SubjectScope, sc) || !client.HasScopes(sc) {
+				return nil, errors.ErrInvalidScope
+			}
+			scopes = append(scopes, sc)
+		}
+	}
+	expiresIn := time.Until(req.SubjectExpiresAt)
+	if expiresIn <= 0 {
+		return nil, errors.ErrInvalidGrant
+	}
+	if expiresIn > manage.DefaultAuthorizeCodeTokenCfg.AccessTokenExp {
+		expiresIn = manage.DefaultAuthorizeCodeTokenCfg.AccessTokenExp
+	}
+	data := &domain.TokenExchange{
+		SubjectID: req.SubjectID,
+		Scope:     strings.Join(scopes, " "),
+		Audience:  req.Audience,
+		ExpiresIn: expiresIn,
+		Actor:     req.SubjectActor,
+	}
+	if req.ActorID != "" {
+		data.Actor = &domain.Actor{Subject: req.ActorID, Actor: req.SubjectActor}
+	}
+	return data, nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sugaml/authserver/internal/core/domain"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+func newExchangeTestService(t *testing.T) *Service {
+	t.Helper()
+	repo := newFakeRepository()
+	addTestClients(t, repo)
+	client := repo.clients.clients["confidential"]
+	client.ClientGrantTypes = []domain.ClientGrantType{{GrantType: domain.GrantTypeTokenExchange}}
+	client.ClientScopes = []domain.ClientScope{{Scope: "api"}}
+	client.ClientProperties = []domain.ClientProperty{{Key: domain.ClientPropertyExchangeAudience, Value: "backend"}}
+	return newTestService(repo)
+}
+
+func newExchangeRequest(edit func(req *domain.TokenExchangeRequest)) *domain.TokenExchangeRequest {
+	req := &domain.TokenExchangeRequest{
+		ClientID:         "confidential",
+		SubjectID:        "1",
+		SubjectScope:     "openid api",
+		SubjectExpiresAt: time.Now().Add(time.Minute),
+		SubjectAudience:  domain.Audience{"confidential"},
+		Audience:         "backend",
+	}
+	if edit != nil {
+		edit(req)
+	}
+	return req
+}
+
+func TestExchangeToken(t *testing.T) {
+	svc := newExchangeTestService(t)
+	tests := []struct {
+		name string
+		edit func(req *domain.TokenExchangeRequest)
+		want error
+	}{
+		{"valid exchange", nil, nil},
+		{"subject token for another audience", func(r *domain.TokenExchangeRequest) { r.SubjectAudience = domain.Audience{"other"} }, errors.ErrInvalidGrant},
+		{"subject token without audience", func(r *domain.TokenExchangeRequest) { r.SubjectAudience = nil }, errors.ErrInvalidGrant},
+		{"audience outside the policy", func(r *domain.TokenExchangeRequest) { r.Audience = "other" }, errInvalidTarget},
+		{"scope beyond the subject token", func(r *domain.TokenExchangeRequest) { r.Scope = "admin" }, errors.ErrInvalidScope},
+		{"expired subject token", func(r *domain.TokenExchangeRequest) { r.SubjectExpiresAt = time.Now().Add(-time.Minute) }, errors.ErrInvalidGrant},
+		{"certificate bound without certificate", func(r *domain.TokenExchangeRequest) {
+			r.SubjectCnf = &domain.Confirmation{X5tS256: "thumbprint"}
+		}, errors.ErrInvalidGrant},
+		{"certificate bound with certificate", func(r *domain.TokenExchangeRequest) {
+			r.SubjectCnf = &domain.Confirmation{X5tS256: "thumbprint"}
+			r.Confirmation.X5tS256 = "thumbprint"
+		}, nil},
+		{"DPoP bound with another key", func(r *domain.TokenExchangeRequest) {
+			r.SubjectCnf = &domain.Confirmation{JKT: "jkt"}
+			r.Confirmation.JKT = "other"
+		}, errors.ErrInvalidGrant},
+		{"DPoP bound with its key", func(r *domain.TokenExchangeRequest) {
+			r.SubjectCnf = &domain.Confirmation{JKT: "jkt"}
+			r.Confirmation.JKT = "jkt"
+		}, nil},
+		{"public client", func(r *domain.TokenExchangeRequest) { r.ClientID = "public" }, errors.ErrInvalidClient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.ExchangeToken(context.Background(), newExchangeRequest(tt.edit)); err != tt.want {
+				t.Errorf("ExchangeToken() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExchangeTokenActorChain(t *testing.T) {
+	svc := newExchangeTestService(t)
+	previous := &domain.Actor{Subject: "gateway"}
+
+	data, err := svc.ExchangeToken(context.Background(), newExchangeRequest(func(r *domain.TokenExchangeRequest) {
+		r.SubjectActor = previous
+		r.ActorID = "confidential"
+	}))
+	if err != nil {
+		t.Fatalf("ExchangeToken() error = %v", err)
+	}
+	if data.Actor == nil || data.Actor.Subject != "confidential" || data.Actor.Actor != previous {
+		t.Errorf("ExchangeToken() act = %+v, want the new actor wrapping the previous one", data.Actor)
+	}
+
+	data, err = svc.ExchangeToken(context.Background(), newExchangeRequest(func(r *domain.TokenExchangeRequest) {
+		r.SubjectActor = previous
+	}))
+	if err != nil {
+		t.Fatalf("ExchangeToken() error = %v", err)
+	}
+	if data.Actor != previous {
+		t.Errorf("ExchangeToken() act = %+v, want the act of the subject token", data.Actor)
+	}
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sugaml/authserver/internal/adapter/config"
+	"github.com/sugaml/authserver/internal/adapter/storage/postgres/repository"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/port"
+)
+
+// fakeRepository keeps clients, persisted grants, audit messages and keys in memory. The embedded
+// interface is nil, so a test calling any other repository panics instead of passing silently.
+type fakeRepository struct {
+	repository.IRepository
+	clients *fakeClientRepository
+	grants  *fakeGrantRepository
+	audits  *fakeAuditRepository
+	keys    *fakeKeyRepository
+}
+
+func newFakeRepository() *fakeRepository {
+	grants := &fakeGrantRepository{grants: map[string]*domain.PersistedGrant{}}
+	return &fakeRepository{
+		clients: &fakeClientRepository{clients: map[string]*domain.Client{}, grants: grants},
+		grants:  grants,
+		audits:  &fakeAuditRepository{},
+		keys:    &fakeKeyRepository{},
+	}
+}
+
+func newTestService(repo *fakeRepository) *Service {
+	return &Service{
+		repo:   repo,
+		config: &config.Token{Issuer: "https://auth.example.com"},
+	}
+}
+
+func (r *fakeRepository) Client() port.ClientRepository {
+	return r.clients
+}
+
+func (r *fakeRepository) PersistedGrant() port.PersistedGrantRepository {
+	return r.grants
+}
+
+func (r *fakeRepository) AuditMessage() port.AuditMessageRepository {
+	return r.audits
+}
+
+func (r *fakeRepository) Key() port.KeyRepository {
+	return r.keys
+}
+
+type fakeClientRepository struct {
+	port.ClientRepository
+	clients map[string]*domain.Client
+	grants  *fakeGrantRepository
+}
+
+func (r *fakeClientRepository) GetDetailByClientID(ctx context.Context, clientID string) (*domain.Client, error) {
+	client, ok := r.clients[clientID]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	return client, nil
+}
+
+func (r *fakeClientRepository) GetCliendID(ctx context.Context, clientID string) (*domain.Client, error) {
+	return r.GetDetailByClientID(ctx, clientID)
+}
+
+func (r *fakeClientRepository) CreateRegistration(ctx context.Context, data *domain.Client, token *domain.PersistedGrant) (*domain.Client, error) {
+	r.clients[data.ClientID] = data
+	if r.grants != nil {
+		if _, err := r.grants.Create(ctx, token); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func (r *fakeClientRepository) UpdateRegistration(ctx context.Context, data *domain.Client) (*domain.Client, error) {
+	r.clients[data.ClientID] = data
+	return data, nil
+}
+
+type fakeGrantRepository struct {
+	port.PersistedGrantRepository
+	grants map[string]*domain.PersistedGrant
+}
+
+func (r *fakeGrantRepository) Create(ctx context.Context, data *domain.PersistedGrant) (*domain.PersistedGrant, error) {
+	if _, ok := r.grants[data.Key]; ok {
+		return nil, domain.ErrConflictingData
+	}
+	r.grants[data.Key] = data
+	return data, nil
+}
+
+func (r *fakeGrantRepository) Get(ctx context.Context, key string) (*domain.PersistedGrant, error) {
+	grant, ok := r.grants[key]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	return grant, nil
+}
+
+func (r *fakeGrantRepository) Delete(ctx context.Context, key string) error {
+	delete(r.grants, key)
+	return nil
+}
+
+func (r *fakeGrantRepository) Redeem(ctx context.Context, key string) error {
+	if _, ok := r.grants[key]; !ok {
+		return domain.ErrDataNotFound
+	}
+	delete(r.grants, key)
+	return nil
+}
+
+func (r *fakeGrantRepository) Consume(ctx context.Context, key string, consumed time.Time) error {
+	grant, ok := r.grants[key]
+	if !ok || grant.ConsumedTime != nil {
+		return domain.ErrDataNotFound
+	}
+	grant.ConsumedTime = &consumed
+	return nil
+}
+
+func (r *fakeGrantRepository) ListByFamily(ctx context.Context, familyID string) ([]*domain.PersistedGrant, error) {
+	var datas []*domain.PersistedGrant
+	for _, grant := range r.grants {
+		if grant.FamilyID == familyID {
+			datas = append(datas, grant)
+		}
+	}
+	return datas, nil
+}
+
+func (r *fakeGrantRepository) DeleteByFamily(ctx context.Context, familyID string) error {
+	for key, grant := range r.grants {
+		if grant.FamilyID == familyID {
+			delete(r.grants, key)
+		}
+	}
+	return nil
+}
+
+type fakeAuditRepository struct {
+	port.AuditMessageRepository
+	messages []*domain.AuditMessage
+}
+
+func (r *fakeAuditRepository) Create(ctx context.Context, data *domain.AuditMessage) (*domain.AuditMessage, error) {
+	r.messages = append(r.messages, data)
+	return data, nil
+}
+
+type fakeKeyRepository struct {
+	port.KeyRepository
+	keys []*domain.Key
+}
+
+// List returns the keys ordered from the newest version, like the postgres repository
+func (r *fakeKeyRepository) List(ctx context.Context, use string) ([]*domain.Key, error) {
+	var datas []*domain.Key
+	for i := len(r.keys) - 1; i >= 0; i-- {
+		if r.keys[i].Use == use {
+			datas = append(datas, r.keys[i])
+		}
+	}
+	return datas, nil
+}
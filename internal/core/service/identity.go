@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+// CreateIdentityResource adds an identity scope. The user claims of its claim types are released in
+// id_tokens and at the userinfo endpoint when the scope is granted.
+func (s *Service) CreateIdentityResource(ctx context.Context, req *domain.IdentityResourceRequest) (*domain.IdentityResourceResponse, error) {
+	logrus.Info("package service CreateIdentityResource() IdentityResource function called.")
+	data := &domain.IdentityResource{}
+	data.New(req)
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkIdentityResourceName(ctx, data.Name, ""); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.IdentityResource().Create(ctx, data)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	return result.Response(), nil
+}
+
+// ListIdentityResource returns every identity resource with its claims and properties
+func (s *Service) ListIdentityResource(ctx context.Context) ([]*domain.IdentityResourceResponse, error) {
+	logrus.Info("package service ListIdentityResource() IdentityResource function called.")
+	results, err := s.repo.IdentityResource().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	datas := []*domain.IdentityResourceResponse{}
+	for _, result := range results {
+		datas = append(datas, result.Response())
+	}
+	return datas, nil
+}
+
+// GetIdentityResource returns an identity resource by id
+func (s *Service) GetIdentityResource(ctx context.Context, id string) (*domain.IdentityResourceResponse, error) {
+	logrus.Info("package service GetIdentityResource() IdentityResource function called.")
+	result, err := s.repo.IdentityResource().Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return result.Response(), nil
+}
+
+// UpdateIdentityResource replaces an identity resource, its claims and properties included
+func (s *Service) UpdateIdentityResource(ctx context.Context, id string, req *domain.IdentityResourceUpdateRequest) (*domain.IdentityResourceResponse, error) {
+	logrus.Info("package service UpdateIdentityResource() IdentityResource function called.")
+	data, err := s.repo.IdentityResource().Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	data.New(req)
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkIdentityResourceName(ctx, data.Name, data.ID); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.IdentityResource().Update(ctx, data)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	return result.Response(), nil
+}
+
+// DeleteIdentityResource removes an identity resource with its claims and properties
+func (s *Service) DeleteIdentityResource(ctx context.Context, id string) error {
+	logrus.Info("package service DeleteIdentityResource() IdentityResource function called.")
+	if _, err := s.repo.IdentityResource().Get(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.IdentityResource().Delete(ctx, id)
+}
+
+// checkIdentityResourceName rejects a name already used by another identity resource or an api scope
+func (s *Service) checkIdentityResourceName(ctx context.Context, name, id string) error {
+	identity, err := s.repo.IdentityResource().GetByName(ctx, name)
+	if err == nil && identity.ID != id {
+		return domain.ErrConflictingData
+	}
+	if err != nil && err != domain.ErrDataNotFound {
+		return domain.ErrInternal
+	}
+	scopes, err := s.repo.ApiScope().ListByNames(ctx, []string{name})
+	if err != nil {
+		return domain.ErrInternal
+	}
+	if len(scopes) > 0 {
+		return domain.ErrConflictingData
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+// AuthenticateIntrospection accepts client credentials or the ApiSecret of an enabled api resource
+func (s *Service) AuthenticateIntrospection(ctx context.Context, creds *domain.ClientCredentials) (string, error) {
+	logrus.Info("package service AuthenticateIntrospection() introspection function called.")
+	if creds.ClientID == "" || !creds.HasCredentials() {
+		return "", errors.ErrInvalidClient
+	}
+	if client, err := s.repo.Client().GetDetailByClientID(ctx, creds.ClientID); err == nil && client.Enabled {
+		if s.authenticateClientCredentials(ctx, client, creds) == nil {
+			return "", nil
+		}
+	}
+	if creds.Secret == "" {
+		return "", errors.ErrInvalidClient
+	}
+	resource, err := s.repo.Resource().GetByName(ctx, creds.ClientID)
+	if err != nil || !resource.Enabled {
+		return "", errors.ErrInvalidClient
+	}
+	secrets, err := s.repo.ApiSecret().ListByApiResourceID(ctx, resource.ID)
+	if err != nil {
+		return "", err
+	}
+	for _, as := range secrets {
+		if matchSecret(as.Value, creds.Secret, as.Expiration) {
+			return resource.Name, nil
+		}
+	}
+	return "", errors.ErrInvalidClient
+}
+
+// CompleteIntrospection sets the audience to the api resources of the token scopes, unless the
+// token names its audience.
+// An api resource only sees tokens issued for it or tokens without an audience.
+func (s *Service) CompleteIntrospection(ctx context.Context, resource string, data *domain.IntrospectionResponse) (*domain.IntrospectionResponse, error) {
+	if !data.Active {
+		return data, nil
+	}
+	if scopes := strings.Fields(data.Scope); len(scopes) > 0 && len(data.Audience) == 0 {
+		names, err := s.repo.ApiScope().ListResourceNames(ctx, scopes)
+		if err != nil {
+			return nil, err
+		}
+		data.Audience = names
+	}
+	if resource == "" || len(data.Audience) == 0 {
+		return data, nil
+	}
+	for _, aud := range data.Audience {
+		if aud == resource {
+			return data, nil
+		}
+	}
+	return &domain.IntrospectionResponse{Active: false}, nil
+}
+
+// matchSecret compares a secret with a stored hash and rejects it once expired
+func matchSecret(stored, secret string, expiration *time.Time) bool {
+	if expiration != nil && expiration.Before(time.Now()) {
+		return false
+	}
+	return util.VerifySecret(stored, secret)
+}
+
+// verifyClientSecret reports whether the secret matches any unexpired shared secret of the client
+func verifyClientSecret(client *domain.Client, secret string) bool {
+	for _, cs := range client.ClientSecrets {
+		if cs.IsSharedSecret() && matchSecret(cs.Value, secret, cs.Expiration) {
+			return true
+		}
+	}
+	return false
+}
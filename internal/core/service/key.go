@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
+)
+
+// Default key lifecycle durations, used when the configuration is empty or invalid
+const (
+	defaultKeyActivationDelay  = 24 * time.Hour
+	defaultKeyRotationInterval = 90 * 24 * time.Hour
+	defaultKeyRetention        = 24 * time.Hour
+	keyRotationCheckInterval   = 10 * time.Minute
+)
+
+// keyPhase is a signing key together with its computed lifecycle status
+type keyPhase struct {
+	key    *domain.Key
+	status string
+}
+
+// EnsureSigningKey creates a signing key with the configured algorithm when none exists
+func (s *Service) EnsureSigningKey(ctx context.Context) error {
+	keys, err := s.repo.Key().List(ctx, domain.KeyUseSignature)
+	if err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return nil
+	}
+	_, err = s.CreateSigningKey(ctx, s.config.SigningAlgorithm)
+	return err
+}
+
+// CreateSigningKey generates a key pair and stores the private key as the next version
+func (s *Service) CreateSigningKey(ctx context.Context, algorithm string) (*domain.KeyResponse, error) {
+	logrus.Info("package service CreateSigningKey() key function called.")
+	privateKey, err := util.GenerateSigningKey(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	data, err := util.EncodePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	protected := s.config.KeySecret != ""
+	if protected {
+		data, err = util.Protect(s.config.KeySecret, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	keys, err := s.repo.Key().List(ctx, domain.KeyUseSignature)
+	if err != nil {
+		return nil, err
+	}
+	version := 1
+	if len(keys) > 0 {
+		version = keys[0].Version + 1
+	}
+	result, err := s.repo.Key().Create(ctx, &domain.Key{
+		ID:            uuid.New().String(),
+		Version:       version,
+		Created:       time.Now(),
+		Use:           domain.KeyUseSignature,
+		Algorithm:     algorithm,
+		DataProtected: protected,
+		Data:          data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	response := domain.Convert[domain.Key, domain.KeyResponse](result)
+	response.Status = domain.KeyStatusPending
+	if len(keys) == 0 {
+		response.Status = domain.KeyStatusActive
+	}
+	return response, nil
+}
+
+// ListSigningKeys returns every signing key with its lifecycle phase
+func (s *Service) ListSigningKeys(ctx context.Context) ([]*domain.KeyResponse, error) {
+	phases, err := s.keyPhases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var datas []*domain.KeyResponse
+	for _, phase := range phases {
+		data := domain.Convert[domain.Key, domain.KeyResponse](phase.key)
+		data.Status = phase.status
+		datas = append(datas, data)
+	}
+	return datas, nil
+}
+
+// RotateSigningKeys pre-publishes the next key once the active key reaches the rotation
+// interval and deletes retired keys whose tokens have all expired. Key versions are unique,
+// so when replicas rotate at the same time only one of them creates the next key.
+func (s *Service) RotateSigningKeys(ctx context.Context, force bool) error {
+	phases, err := s.keyPhases(ctx)
+	if err != nil {
+		return err
+	}
+	pending := false
+	var active *domain.Key
+	for _, phase := range phases {
+		switch phase.status {
+		case domain.KeyStatusPending:
+			pending = true
+		case domain.KeyStatusActive:
+			active = phase.key
+		case domain.KeyStatusExpired:
+			logrus.Info("removing expired signing key version ", phase.key.Version)
+			if err := s.repo.Key().Delete(ctx, phase.key.ID); err != nil {
+				return err
+			}
+		}
+	}
+	due := active == nil ||
+		time.Since(active.Created) >= parseDuration(s.config.KeyRotationInterval, defaultKeyRotationInterval)
+	if force || (due && !pending) {
+		algorithm := s.config.SigningAlgorithm
+		if active != nil && !force {
+			algorithm = active.Algorithm
+		}
+		result, err := s.CreateSigningKey(ctx, algorithm)
+		if err == domain.ErrConflictingData {
+			logrus.Info("signing key already pre-published by another instance")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		logrus.Info("pre-published signing key version ", result.Version)
+	}
+	return nil
+}
+
+// RunKeyRotation checks the signing keys periodically until the context is cancelled
+func (s *Service) RunKeyRotation(ctx context.Context) {
+	ticker := time.NewTicker(keyRotationCheckInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.RotateSigningKeys(ctx, false); err != nil {
+			logrus.Error("Error rotating signing keys", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetSigningKey returns the active signing key
+func (s *Service) GetSigningKey(ctx context.Context) (*domain.SigningKey, error) {
+	phases, err := s.keyPhases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, phase := range phases {
+		if phase.status == domain.KeyStatusActive {
+			return s.decodeKey(phase.key)
+		}
+	}
+	return nil, domain.ErrDataNotFound
+}
+
+// GetJWKS returns the public keys of the pending, active and retired signing keys
+func (s *Service) GetJWKS(ctx context.Context) (*domain.JSONWebKeySet, error) {
+	phases, err := s.keyPhases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jwks := &domain.JSONWebKeySet{Keys: []domain.JSONWebKey{}}
+	for _, phase := range phases {
+		if phase.status == domain.KeyStatusExpired {
+			continue
+		}
+		signingKey, err := s.decodeKey(phase.key)
+		if err != nil {
+			return nil, err
+		}
+		jwk, err := util.NewJSONWebKey(signingKey.ID, signingKey.Algorithm, signingKey.PrivateKey.Public())
+		if err != nil {
+			return nil, err
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	return jwks, nil
+}
+
+// keyPhases derives the lifecycle phase of every signing key from its version and age.
+// The newest key older than the activation delay signs; newer keys are pre-published.
+// Older keys are retired from the moment their successor became active and expire
+// once the retention window, the longest token lifetime, has passed.
+func (s *Service) keyPhases(ctx context.Context) ([]keyPhase, error) {
+	keys, err := s.repo.Key().List(ctx, domain.KeyUseSignature)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	delay := parseDuration(s.config.KeyActivationDelay, defaultKeyActivationDelay)
+	retention := parseDuration(s.config.KeyRetention, defaultKeyRetention)
+	active := len(keys) - 1
+	for i, key := range keys {
+		if now.Sub(key.Created) >= delay {
+			active = i
+			break
+		}
+	}
+	phases := make([]keyPhase, 0, len(keys))
+	for i, key := range keys {
+		status := domain.KeyStatusActive
+		switch {
+		case i < active:
+			status = domain.KeyStatusPending
+		case i > active:
+			status = domain.KeyStatusRetired
+			retiredAt := keys[i-1].Created.Add(delay)
+			if now.After(retiredAt.Add(retention)) {
+				status = domain.KeyStatusExpired
+			}
+		}
+		phases = append(phases, keyPhase{key: key, status: status})
+	}
+	return phases, nil
+}
+
+// decodeKey unprotects and parses the private key stored in a Key row
+func (s *Service) decodeKey(key *domain.Key) (*domain.SigningKey, error) {
+	data := key.Data
+	if key.DataProtected {
+		var err error
+		data, err = util.Unprotect(s.config.KeySecret, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	privateKey, err := util.DecodePrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.SigningKey{
+		ID:         key.ID,
+		Version:    key.Version,
+		Algorithm:  key.Algorithm,
+		PrivateKey: privateKey,
+	}, nil
+}
+
+// parseDuration parses a configured duration, falling back to the default
+func parseDuration(value string, def time.Duration) time.Duration {
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return def
+	}
+	return duration
+}
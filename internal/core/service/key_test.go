@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+func newKeyTestService(ages ...time.Duration) *Service {
+	repo := newFakeRepository()
+	now := time.Now()
+	for i, age := range ages {
+		repo.keys.keys = append(repo.keys.keys, &domain.Key{
+			Version: i + 1,
+			Use:     domain.KeyUseSignature,
+			Created: now.Add(-age),
+		})
+	}
+	svc := newTestService(repo)
+	svc.config.KeyActivationDelay = "1h"
+	svc.config.KeyRetention = "24h"
+	return svc
+}
+
+func TestKeyPhases(t *testing.T) {
+	svc := newKeyTestService(100*time.Hour, 50*time.Hour, 10*time.Hour, 10*time.Minute)
+	phases, err := svc.keyPhases(context.Background())
+	if err != nil {
+		t.Fatalf("keyPhases() error = %v", err)
+	}
+	want := map[int]string{
+		4: domain.KeyStatusPending,
+		3: domain.KeyStatusActive,
+		2: domain.KeyStatusRetired,
+		1: domain.KeyStatusExpired,
+	}
+	if len(phases) != len(want) {
+		t.Fatalf("keyPhases() returned %d phases, want %d", len(phases), len(want))
+	}
+	for _, phase := range phases {
+		if phase.status != want[phase.key.Version] {
+			t.Errorf("keyPhases() key version %d = %s, want %s", phase.key.Version, phase.status, want[phase.key.Version])
+		}
+	}
+}
+
+func TestKeyPhasesFirstKey(t *testing.T) {
+	svc := newKeyTestService(time.Minute)
+	phases, err := svc.keyPhases(context.Background())
+	if err != nil {
+		t.Fatalf("keyPhases() error = %v", err)
+	}
+	if len(phases) != 1 || phases[0].status != domain.KeyStatusActive {
+		t.Errorf("keyPhases() = %+v, want the only key active before the activation delay", phases)
+	}
+}
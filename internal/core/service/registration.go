@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+// defaultRegistrationScopes are the scopes a client may register when TOKEN_REGISTRATION_SCOPES is not set
+const defaultRegistrationScopes = "openid profile email offline_access"
+
+// RegisterClient creates a client from its metadata, see RFC 7591 section 3. The client, its secret
+// and the registration access token are stored together, and the generated secret and token are
+// only returned here.
+func (s *Service) RegisterClient(ctx context.Context, req *domain.ClientRegistrationRequest) (*domain.ClientRegistrationResponse, error) {
+	logrus.Info("package service RegisterClient() registration function called.")
+	req.Prepare()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkRegistrationScope(req.Scope); err != nil {
+		return nil, err
+	}
+	data := &domain.Client{}
+	data.ID = uuid.New().String()
+	data.NewRegistration(req)
+	secret, value, err := s.registrationSecret(data.ID, req)
+	if err != nil {
+		return nil, err
+	}
+	if secret != nil {
+		data.ClientSecrets = append(data.ClientSecrets, *secret)
+	}
+	token, err := util.RandomToken(32)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	client, err := s.repo.Client().CreateRegistration(ctx, data, &domain.PersistedGrant{
+		Key:          domain.GrantKey(token, domain.GrantTypeRegistrationToken),
+		Type:         domain.GrantTypeRegistrationToken,
+		ClientID:     data.ClientID,
+		CreationTime: time.Now(),
+	})
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	res := s.registrationResponse(client, req)
+	res.ClientSecret = value
+	res.RegistrationAccessToken = token
+	return res, nil
+}
+
+// GetClientRegistration returns the registered metadata of the client, see RFC 7592 section 2.1
+func (s *Service) GetClientRegistration(ctx context.Context, clientID, token string) (*domain.ClientRegistrationResponse, error) {
+	logrus.Info("package service GetClientRegistration() registration function called.")
+	client, err := s.getRegisteredClient(ctx, clientID, token)
+	if err != nil {
+		return nil, err
+	}
+	return s.registrationResponse(client, registrationRequest(client)), nil
+}
+
+// UpdateClientRegistration replaces the metadata of the client, see RFC 7592 section 2.2. The token
+// endpoint authentication method is fixed at registration. The registration access token is rotated
+// and the new one is returned in the response.
+func (s *Service) UpdateClientRegistration(ctx context.Context, clientID, token string, req *domain.ClientRegistrationRequest) (*domain.ClientRegistrationResponse, error) {
+	logrus.Info("package service UpdateClientRegistration() registration function called.")
+	client, err := s.getRegisteredClient(ctx, clientID, token)
+	if err != nil {
+		return nil, err
+	}
+	req.Prepare()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkRegistrationScope(req.Scope); err != nil {
+		return nil, err
+	}
+	if req.TokenEndpointAuthMethod != client.Property(domain.ClientPropertyTokenEndpointAuthMethod) {
+		return nil, &domain.ClientMetadataError{Code: "invalid_client_metadata", Description: "token_endpoint_auth_method cannot be changed"}
+	}
+	client.SetRegistration(req)
+	result, err := s.repo.Client().UpdateRegistration(ctx, client)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	if req.TokenEndpointAuthMethod == domain.AuthMethodPrivateKeyJwt {
+		// The new keys are stored before the old ones are removed so a failure keeps the client usable
+		if err := s.createRegistrationSecret(ctx, result.ID, req); err != nil {
+			return nil, err
+		}
+		for _, secret := range result.ClientSecrets {
+			if secret.Type == domain.SecretTypeJsonWebKey {
+				if err := s.repo.ClientSecret().Delete(ctx, secret.ID); err != nil {
+					return nil, domain.ErrInternal
+				}
+			}
+		}
+	}
+	res := s.registrationResponse(result, req)
+	res.RegistrationAccessToken, err = s.rotateRegistrationToken(ctx, result.ClientID, token)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteClientRegistration deletes the client and invalidates its registration access token,
+// see RFC 7592 section 2.3
+func (s *Service) DeleteClientRegistration(ctx context.Context, clientID, token string) error {
+	logrus.Info("package service DeleteClientRegistration() registration function called.")
+	client, err := s.getRegisteredClient(ctx, clientID, token)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Client().Delete(ctx, client.ID); err != nil {
+		return domain.ErrInternal
+	}
+	return s.repo.PersistedGrant().Delete(ctx, domain.GrantKey(token, domain.GrantTypeRegistrationToken))
+}
+
+// rotateRegistrationToken replaces the registration access token of the client with a new one. The new
+// token is stored before the old one is removed so a failure keeps the client manageable.
+func (s *Service) rotateRegistrationToken(ctx context.Context, clientID, old string) (string, error) {
+	token, err := util.RandomToken(32)
+	if err != nil {
+		return "", domain.ErrInternal
+	}
+	if _, err := s.repo.PersistedGrant().Create(ctx, &domain.PersistedGrant{
+		Key:          domain.GrantKey(token, domain.GrantTypeRegistrationToken),
+		Type:         domain.GrantTypeRegistrationToken,
+		ClientID:     clientID,
+		CreationTime: time.Now(),
+	}); err != nil {
+		return "", domain.ErrInternal
+	}
+	if err := s.repo.PersistedGrant().Delete(ctx, domain.GrantKey(old, domain.GrantTypeRegistrationToken)); err != nil {
+		return "", domain.ErrInternal
+	}
+	return token, nil
+}
+
+// getRegisteredClient returns the client the registration access token was issued for
+func (s *Service) getRegisteredClient(ctx context.Context, clientID, token string) (*domain.Client, error) {
+	if clientID == "" || token == "" {
+		return nil, errors.ErrInvalidAccessToken
+	}
+	grant, err := s.repo.PersistedGrant().Get(ctx, domain.GrantKey(token, domain.GrantTypeRegistrationToken))
+	if err != nil || grant.Type != domain.GrantTypeRegistrationToken || grant.ClientID != clientID {
+		return nil, errors.ErrInvalidAccessToken
+	}
+	client, err := s.repo.Client().GetDetailByClientID(ctx, clientID)
+	if err != nil {
+		return nil, errors.ErrInvalidAccessToken
+	}
+	return client, nil
+}
+
+// checkRegistrationScope rejects scopes outside the configured registration policy
+func (s *Service) checkRegistrationScope(scope string) error {
+	allowed := s.config.RegistrationScopes
+	if allowed == "" {
+		allowed = defaultRegistrationScopes
+	}
+	for _, sc := range strings.Fields(scope) {
+		if !domain.HasScope(allowed, sc) {
+			return &domain.ClientMetadataError{Code: "invalid_client_metadata", Description: "scope " + sc + " cannot be registered"}
+		}
+	}
+	return nil
+}
+
+// registrationSecret builds the secret the authentication method needs and returns it with its
+// plain text, which is empty for public keys. Public clients get no secret.
+func (s *Service) registrationSecret(id string, req *domain.ClientRegistrationRequest) (*domain.ClientSecret, string, error) {
+	secret := &domain.ClientSecretRequest{ClientID: id, Description: req.TokenEndpointAuthMethod}
+	switch req.TokenEndpointAuthMethod {
+	case domain.AuthMethodClientSecretBasic, domain.AuthMethodClientSecretPost:
+		secret.Type = domain.SecretTypeSharedSecret
+	case domain.AuthMethodClientSecretJwt:
+		secret.Type = domain.SecretTypeSigningSecret
+	case domain.AuthMethodPrivateKeyJwt:
+		secret.Type = domain.SecretTypeJsonWebKey
+		secret.Value = string(domain.ConvertToJson(req.Jwks))
+	default:
+		return nil, "", nil
+	}
+	data, value, err := s.newClientSecret(secret)
+	if err != nil {
+		return nil, "", &domain.ClientMetadataError{Code: "invalid_client_metadata", Description: err.Error()}
+	}
+	return data, value, nil
+}
+
+// createRegistrationSecret stores the secret the authentication method needs for an existing client
+func (s *Service) createRegistrationSecret(ctx context.Context, id string, req *domain.ClientRegistrationRequest) error {
+	secret, _, err := s.registrationSecret(id, req)
+	if err != nil || secret == nil {
+		return err
+	}
+	if _, err := s.repo.ClientSecret().Create(ctx, secret); err != nil {
+		return domain.ErrInternal
+	}
+	return nil
+}
+
+// registrationRequest rebuilds the registered metadata of a client
+func registrationRequest(client *domain.Client) *domain.ClientRegistrationRequest {
+	req := &domain.ClientRegistrationRequest{
+		ClientName:              client.ClientName,
+		ClientUri:               client.ClientUri,
+		TokenEndpointAuthMethod: client.Property(domain.ClientPropertyTokenEndpointAuthMethod),
+		PostLogoutRedirectUris:  strings.Fields(client.Property(domain.ClientPropertyPostLogoutRedirectUris)),
+		BackChannelLogoutUri:    client.Property(domain.ClientPropertyBackChannelLogoutUri),
+	}
+	for _, uri := range client.ClientRedirectUris {
+		req.RedirectUris = append(req.RedirectUris, uri.RedirectUri)
+	}
+	for _, gt := range client.ClientGrantTypes {
+		req.GrantTypes = append(req.GrantTypes, gt.GrantType)
+	}
+	scopes := []string{}
+	for _, scope := range client.ClientScopes {
+		scopes = append(scopes, scope.Scope)
+	}
+	req.Scope = strings.Join(scopes, " ")
+	for _, secret := range client.ClientSecrets {
+		if secret.Type != domain.SecretTypeJsonWebKey {
+			continue
+		}
+		if keys, err := parseJSONWebKeys(secret.Value); err == nil {
+			if req.Jwks == nil {
+				req.Jwks = &domain.JSONWebKeySet{}
+			}
+			req.Jwks.Keys = append(req.Jwks.Keys, keys...)
+		}
+	}
+	return req
+}
+
+func (s *Service) registrationResponse(client *domain.Client, req *domain.ClientRegistrationRequest) *domain.ClientRegistrationResponse {
+	return &domain.ClientRegistrationResponse{
+		ClientID:                  client.ClientID,
+		ClientIDIssuedAt:          client.CreatedAt.Unix(),
+		RegistrationClientUri:     s.config.Issuer + "/connect/register/" + client.ClientID,
+		ClientRegistrationRequest: *req,
+	}
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+func TestUpdateClientRegistrationRotatesToken(t *testing.T) {
+	repo := newFakeRepository()
+	svc := newTestService(repo)
+	ctx := context.Background()
+	req := &domain.ClientRegistrationRequest{RedirectUris: []string{"com.example.app:/callback"}, TokenEndpointAuthMethod: domain.AuthMethodNone}
+	registered, err := svc.RegisterClient(ctx, req)
+	if err != nil {
+		t.Fatalf("RegisterClient() error = %v", err)
+	}
+	old := registered.RegistrationAccessToken
+
+	update := &domain.ClientRegistrationRequest{RedirectUris: []string{"http://127.0.0.1/callback"}, TokenEndpointAuthMethod: domain.AuthMethodNone}
+	updated, err := svc.UpdateClientRegistration(ctx, registered.ClientID, old, update)
+	if err != nil {
+		t.Fatalf("UpdateClientRegistration() error = %v", err)
+	}
+	if updated.RegistrationAccessToken == "" || updated.RegistrationAccessToken == old {
+		t.Fatal("registration access token not rotated")
+	}
+	if _, err := svc.GetClientRegistration(ctx, registered.ClientID, old); err == nil {
+		t.Fatal("old registration access token accepted")
+	}
+	if _, err := svc.GetClientRegistration(ctx, registered.ClientID, updated.RegistrationAccessToken); err != nil {
+		t.Fatalf("new registration access token rejected: %v", err)
+	}
+}
@@ -18,6 +18,10 @@ func (s *Service) CreateResource(ctx context.Context, req *domain.ResourceReques
 	if err != nil {
 		return nil, err
 	}
+	// The name is the audience of the access tokens issued for the resource
+	if _, err := s.repo.Resource().GetByName(ctx, data.Name); err == nil {
+		return nil, domain.ErrConflictingData
+	}
 	result, err := s.repo.Resource().Create(ctx, data)
 	if err != nil {
 		return nil, fmt.Errorf("encountered %v error create Resource", err)
@@ -25,14 +29,19 @@ func (s *Service) CreateResource(ctx context.Context, req *domain.ResourceReques
 	return domain.Convert[domain.Resource, domain.ResourceResponse](result), nil
 }
 
-// Get returns a Resource by id
+// Get returns a Resource by id with its scopes
 func (s *Service) GetResource(ctx context.Context, id string) (*domain.ResourceResponse, error) {
 	logrus.Info("package service Get() Resource function called.")
 	result, err := s.repo.Resource().Get(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	return domain.Convert[domain.Resource, domain.ResourceResponse](result), nil
+	res := domain.Convert[domain.Resource, domain.ResourceResponse](result)
+	res.Scopes, err = s.ListApiScope(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
 // List returns a list of Resources with pagination
@@ -51,13 +60,21 @@ func (s *Service) ListResource(ctx context.Context, req *domain.ResourceListRequ
 
 // Update updates a Resource
 func (cs *Service) UpdateResource(ctx context.Context, id string, req *domain.ResourceUpdateRequest) (*domain.ResourceResponse, error) {
-	_, err := cs.repo.Resource().Get(ctx, id)
+	current, err := cs.repo.Resource().Get(ctx, id)
 	if err != nil {
 		if err == domain.ErrDataNotFound {
 			return nil, err
 		}
 		return nil, domain.ErrInternal
 	}
+	if req.Name == "" {
+		return nil, errors.New("required resource name")
+	}
+	if req.Name != current.Name {
+		if _, err := cs.repo.Resource().GetByName(ctx, req.Name); err == nil {
+			return nil, domain.ErrConflictingData
+		}
+	}
 	mp := req.NewUpdate()
 	result, err := cs.repo.Resource().Update(ctx, id, mp)
 	if err != nil {
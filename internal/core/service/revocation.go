@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+// AuthenticateClient accepts an enabled public client by id or a confidential client
+// by secret or client assertion. A public client has no secret or keys, so one presenting
+// a secret or assertion is rejected rather than letting any value pass.
+func (s *Service) AuthenticateClient(ctx context.Context, creds *domain.ClientCredentials) error {
+	logrus.Info("package service AuthenticateClient() revocation function called.")
+	if creds.ClientID == "" {
+		return errors.ErrInvalidClient
+	}
+	client, err := s.repo.Client().GetDetailByClientID(ctx, creds.ClientID)
+	if err != nil || !client.Enabled {
+		return errors.ErrInvalidClient
+	}
+	if client.IsPublic() {
+		if creds.Secret != "" || creds.Assertion != "" {
+			return errors.ErrInvalidClient
+		}
+		return nil
+	}
+	return s.authenticateClientCredentials(ctx, client, creds)
+}
+
+// RevokeRefreshToken deletes every refresh and access token issued in the family of the refresh token
+func (s *Service) RevokeRefreshToken(ctx context.Context, refresh string) error {
+	logrus.Info("package service RevokeRefreshToken() revocation function called.")
+	family := domain.RefreshTokenFamily(refresh)
+	if family == "" {
+		return nil
+	}
+	return s.repo.PersistedGrant().DeleteByFamily(ctx, family)
+}
+
+// RevokeToken stores the token id on the denylist until the token would have expired
+func (s *Service) RevokeToken(ctx context.Context, payload *domain.TokenPayload) error {
+	logrus.Info("package service RevokeToken() revocation function called.")
+	revoked, err := s.IsTokenRevoked(ctx, payload.ID)
+	if err != nil || revoked {
+		return err
+	}
+	_, err = s.repo.PersistedGrant().Create(ctx, &domain.PersistedGrant{
+		Key:          domain.GrantKey(payload.ID.String(), domain.GrantTypeRevokedToken),
+		Type:         domain.GrantTypeRevokedToken,
+		SubjectID:    strconv.FormatUint(payload.UserID, 10),
+		CreationTime: time.Now(),
+		Expiration:   payload.ExpiredAt,
+	})
+	return err
+}
+
+// IsTokenRevoked reports whether the token id is on the denylist
+func (s *Service) IsTokenRevoked(ctx context.Context, id uuid.UUID) (bool, error) {
+	_, err := s.repo.PersistedGrant().Get(ctx, domain.GrantKey(id.String(), domain.GrantTypeRevokedToken))
+	if err == domain.ErrDataNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
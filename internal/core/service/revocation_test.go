@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+// addTestClients stores an enabled public client, an enabled confidential client with the
+// shared secret "s3cret" and a disabled public client
+func addTestClients(t *testing.T, repo *fakeRepository) {
+	t.Helper()
+	hash, err := util.HashSecret("s3cret")
+	if err != nil {
+		t.Fatalf("HashSecret() error = %v", err)
+	}
+	repo.clients.clients["public"] = &domain.Client{ClientID: "public", Enabled: true}
+	repo.clients.clients["confidential"] = &domain.Client{
+		ClientID:      "confidential",
+		Enabled:       true,
+		ClientSecrets: []domain.ClientSecret{{Type: domain.SecretTypeSharedSecret, Value: hash}},
+	}
+	repo.clients.clients["disabled"] = &domain.Client{ClientID: "disabled"}
+}
+
+func TestAuthenticateClient(t *testing.T) {
+	repo := newFakeRepository()
+	addTestClients(t, repo)
+	svc := newTestService(repo)
+
+	tests := []struct {
+		name  string
+		creds domain.ClientCredentials
+		want  error
+	}{
+		{"public client by id", domain.ClientCredentials{ClientID: "public"}, nil},
+		{"public client with secret", domain.ClientCredentials{ClientID: "public", Secret: "anything"}, errors.ErrInvalidClient},
+		{"public client with assertion", domain.ClientCredentials{ClientID: "public", Assertion: "a.b.c"}, errors.ErrInvalidClient},
+		{"confidential client with secret", domain.ClientCredentials{ClientID: "confidential", Secret: "s3cret"}, nil},
+		{"confidential client with wrong secret", domain.ClientCredentials{ClientID: "confidential", Secret: "wrong"}, errors.ErrInvalidClient},
+		{"confidential client without secret", domain.ClientCredentials{ClientID: "confidential"}, errors.ErrInvalidClient},
+		{"disabled client", domain.ClientCredentials{ClientID: "disabled"}, errors.ErrInvalidClient},
+		{"unknown client", domain.ClientCredentials{ClientID: "unknown"}, errors.ErrInvalidClient},
+		{"missing client id", domain.ClientCredentials{}, errors.ErrInvalidClient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := svc.AuthenticateClient(context.Background(), &tt.creds); err != tt.want {
+				t.Errorf("AuthenticateClient() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -7,14 +7,19 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
 )
 
-// Create a new Secret
+// clientSecretLength is the number of random bytes in a generated secret
+const clientSecretLength = 32
+
+// Create a new Secret. A shared secret is always generated, as its single SHA-256 hash is only
+// safe for high entropy values, and a signing secret is generated when the request omits it.
+// Both are stored hashed or encrypted, so the response is the only place the plain text is
+// returned. Public keys and certificate names or thumbprints are stored as given.
 func (s *Service) CreateSecret(ctx context.Context, req *domain.ClientSecretRequest) (*domain.ClientSecretResponse, error) {
 	logrus.Info("package service Create() Secret function called.")
-	data := domain.Convert[domain.ClientSecretRequest, domain.ClientSecret](req)
-	data.New(req)
-	err := data.Validate()
+	data, value, err := s.newClientSecret(req)
 	if err != nil {
 		return nil, err
 	}
@@ -22,7 +27,66 @@ func (s *Service) CreateSecret(ctx context.Context, req *domain.ClientSecretRequ
 	if err != nil {
 		return nil, fmt.Errorf("encountered %v error create Secret", err)
 	}
-	return domain.Convert[domain.ClientSecret, domain.ClientSecretResponse](result), nil
+	res := domain.Convert[domain.ClientSecret, domain.ClientSecretResponse](result)
+	res.Value = value
+	return res, nil
+}
+
+// newClientSecret validates the request and returns the secret to store together with the
+// plain text value to return, which is empty for public keys and certificates
+func (s *Service) newClientSecret(req *domain.ClientSecretRequest) (*domain.ClientSecret, string, error) {
+	data := domain.Convert[domain.ClientSecretRequest, domain.ClientSecret](req)
+	data.New(req)
+	err := data.Validate()
+	if err != nil {
+		return nil, "", err
+	}
+	value := req.Value
+	if value != "" && data.Type == domain.SecretTypeSharedSecret {
+		return nil, "", errors.New("shared secret values are generated and cannot be set")
+	}
+	if value == "" && (data.Type == domain.SecretTypeSharedSecret || data.Type == domain.SecretTypeSigningSecret) {
+		value, err = util.RandomToken(clientSecretLength)
+		if err != nil {
+			return nil, "", domain.ErrInternal
+		}
+	}
+	switch data.Type {
+	case domain.SecretTypeSharedSecret:
+		data.Value, err = util.HashSecret(value)
+		if err != nil {
+			return nil, "", domain.ErrInternal
+		}
+	case domain.SecretTypeSigningSecret:
+		// The plain value is needed to verify HMAC signatures, so it is encrypted rather than hashed
+		if s.config.KeySecret == "" {
+			return nil, "", errors.New("signing secrets require a key secret to encrypt them")
+		}
+		data.Value, err = util.Protect(s.config.KeySecret, value)
+		if err != nil {
+			return nil, "", domain.ErrInternal
+		}
+	case domain.SecretTypeJsonWebKey:
+		jwks, err := parseJSONWebKeys(value)
+		if err != nil {
+			return nil, "", errors.New("value must be a public JWK or JWKS")
+		}
+		for _, jwk := range jwks {
+			if _, err := util.ParseJSONWebKey(jwk); err != nil {
+				return nil, "", err
+			}
+		}
+		// Only the public members are kept
+		data.Value = string(domain.ConvertToJson(domain.JSONWebKeySet{Keys: jwks}))
+		value = ""
+	case domain.SecretTypeX509Name, domain.SecretTypeX509Thumbprint:
+		if value == "" {
+			return nil, "", errors.New("value must be a certificate subject DN or thumbprint")
+		}
+		data.Value = value
+		value = ""
+	}
+	return data, value, nil
 }
 
 // Get returns a Secret by id
@@ -57,13 +121,16 @@ func (s *Service) ListSecret(ctx context.Context, req *domain.ClientSecretListRe
 		return nil, count, err
 	}
 	for _, result := range results {
-		datas = append(datas, domain.Convert[domain.ClientSecret, domain.ClientSecretResponse](result), nil)
+		datas = append(datas, domain.Convert[domain.ClientSecret, domain.ClientSecretResponse](result))
 	}
 	return datas, count, nil
 }
 
 // Update updates a Secret
 func (cs *Service) UpdateSecret(ctx context.Context, id string, req *domain.ClientSecretUpdateRequest) (*domain.ClientSecretResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
 	_, err := cs.repo.ClientSecret().Get(ctx, id)
 	if err != nil {
 		if err == domain.ErrDataNotFound {
@@ -72,6 +139,9 @@ func (cs *Service) UpdateSecret(ctx context.Context, id string, req *domain.Clie
 		return nil, domain.ErrInternal
 	}
 	mp := req.NewUpdate()
+	if len(mp) == 0 {
+		return nil, domain.ErrNoUpdatedData
+	}
 	result, err := cs.repo.ClientSecret().Update(ctx, id, mp)
 	if err != nil {
 		if err == domain.ErrConflictingData {
@@ -3,42 +3,59 @@ package service
 import (
 	"log"
 
+	"github.com/sugaml/authserver/internal/adapter/config"
 	"github.com/sugaml/authserver/internal/adapter/storage/postgres/repository"
 	"github.com/sugaml/authserver/internal/core/port"
+	"gopkg.in/oauth2.v3"
 	"gopkg.in/oauth2.v3/errors"
 	"gopkg.in/oauth2.v3/manage"
 	"gopkg.in/oauth2.v3/server"
-	"gopkg.in/oauth2.v3/store"
 )
 
 type Service struct {
-	repo repository.IRepository
+	repo   repository.IRepository
+	config *config.Token
 }
 
-func NewService(repo repository.IRepository) port.IService {
+func NewService(repo repository.IRepository, config *config.Token) port.IService {
 	return &Service{
-		repo: repo,
+		repo:   repo,
+		config: config,
 	}
 }
 
-func GetOauthServer(repo repository.IRepository) *server.Server {
+func GetOauthServer(repo repository.IRepository, svc port.IService, config *config.Token) *server.Server {
 	manager := manage.NewDefaultManager()
 	manager.SetAuthorizeCodeTokenCfg(manage.DefaultAuthorizeCodeTokenCfg)
 
-	// Token memory store
-	manager.MustTokenStorage(store.NewMemoryTokenStore())
+	// Token database store
+	manager.MapTokenStorage(newTokenStore(repo))
 
-	clientStore := newClientStotreService(repo)
+	clientStore := newClientStotreService(repo, svc, svc)
 	// Client database store
 	manager.MapClientStorage(clientStore)
+	// Redirect uris are matched exactly against ClientRedirectUris by the authorize service
+	manager.SetValidateURIHandler(func(baseURI, redirectURI string) error {
+		return nil
+	})
 
 	// Set custom JWT generator
-	manager.MapAccessGenerate(&JWTAccessGenerate{})
+	manager.MapAccessGenerate(&JWTAccessGenerate{keys: svc, resources: svc, issuer: config.Issuer})
 
 	srv := server.NewDefaultServer(manager)
 	srv.SetAllowGetAccessRequest(true)
-	srv.SetClientInfoHandler(server.ClientFormHandler)
-	manager.SetRefreshTokenCfg(manage.DefaultRefreshTokenCfg)
+	srv.SetClientInfoHandler(clientStore.ClientInfoHandler)
+	srv.SetClientAuthorizedHandler(clientStore.ClientAuthorizedHandler)
+	srv.SetClientScopeHandler(clientStore.ClientScopeHandler)
+	srv.SetRefreshingScopeHandler(refreshingScopeHandler)
+	srv.SetPasswordAuthorizationHandler(passwordAuthorizationHandler(svc))
+	srv.Config.AllowedResponseTypes = []oauth2.ResponseType{oauth2.Code}
+	// Rotate refresh tokens, the old access and refresh token stop working on every refresh
+	manager.SetRefreshTokenCfg(&manage.RefreshingConfig{
+		IsGenerateRefresh:  true,
+		IsRemoveAccess:     true,
+		IsRemoveRefreshing: true,
+	})
 
 	srv.SetInternalErrorHandler(func(err error) (re *errors.Response) {
 		log.Println("Internal Error:", err.Error())
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"github.com/sugaml/authserver/internal/core/util"
+	"gopkg.in/oauth2.v3/errors"
+)
+
+const (
+	// defaultSessionLifetime is how long a sign in session lasts when the configuration is empty or invalid
+	defaultSessionLifetime = 8 * time.Hour
+	// logoutTokenLifetime is how long a back-channel logout token is valid after it is issued
+	logoutTokenLifetime = 2 * time.Minute
+)
+
+// logoutClient posts back-channel logout tokens, a client that does not answer in time is skipped
+var logoutClient = &http.Client{Timeout: 5 * time.Second}
+
+// CreateUserSession starts a sign in session for the subject. The session cookie value only keys
+// the session row, its grants are grouped under a separate random sid that clients may see.
+func (s *Service) CreateUserSession(ctx context.Context, subjectID string) (*domain.UserSession, error) {
+	logrus.Info("package service CreateUserSession() session function called.")
+	sessionID, err := util.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	sid, err := util.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	_, err = s.repo.PersistedGrant().Create(ctx, &domain.PersistedGrant{
+		Key:          domain.GrantKey(sessionID, domain.GrantTypeUserSession),
+		Type:         domain.GrantTypeUserSession,
+		SubjectID:    subjectID,
+		FamilyID:     sid,
+		CreationTime: now,
+		Expiration:   now.Add(parseDuration(s.config.SessionLifetime, defaultSessionLifetime)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.UserSession{SessionID: sessionID, Sid: sid, SubjectID: subjectID, AuthTime: now}, nil
+}
+
+// GetUserSession returns the unexpired sign in session of a session cookie. Sessions started before
+// the sid was separated from the cookie value are ignored, so their cookie never reaches a client.
+func (s *Service) GetUserSession(ctx context.Context, sessionID string) (*domain.UserSession, error) {
+	logrus.Info("package service GetUserSession() session function called.")
+	if sessionID == "" {
+		return nil, domain.ErrDataNotFound
+	}
+	grant, err := s.repo.PersistedGrant().Get(ctx, domain.GrantKey(sessionID, domain.GrantTypeUserSession))
+	if err != nil {
+		return nil, domain.ErrDataNotFound
+	}
+	if grant.Type != domain.GrantTypeUserSession || grant.Expiration.Before(time.Now()) || grant.FamilyID == sessionID {
+		return nil, domain.ErrDataNotFound
+	}
+	return &domain.UserSession{SessionID: sessionID, Sid: grant.FamilyID, SubjectID: grant.SubjectID, AuthTime: grant.CreationTime}, nil
+}
+
+// AddSessionClient records that the client took part in the session, so it is signed out with it
+func (s *Service) AddSessionClient(ctx context.Context, sessionID, clientID string) error {
+	logrus.Info("package service AddSessionClient() session function called.")
+	session, err := s.repo.PersistedGrant().Get(ctx, domain.GrantKey(sessionID, domain.GrantTypeUserSession))
+	if err != nil {
+		return err
+	}
+	key := domain.GrantKey(session.FamilyID+":"+clientID, domain.GrantTypeSessionClient)
+	if _, err := s.repo.PersistedGrant().Get(ctx, key); err == nil {
+		return nil
+	}
+	_, err = s.repo.PersistedGrant().Create(ctx, &domain.PersistedGrant{
+		Key:          key,
+		Type:         domain.GrantTypeSessionClient,
+		SubjectID:    session.SubjectID,
+		ClientID:     clientID,
+		FamilyID:     session.FamilyID,
+		CreationTime: time.Now(),
+		Expiration:   session.Expiration,
+	})
+	return err
+}
+
+// EndSession validates an RP-initiated logout request, ends the session and signs the user out of
+// every client that took part in it. The session is found by its sid, taken from the session cookie
+// or else from the id_token_hint. Clients with a back-channel logout uri are sent a logout token
+// here, the front-channel logout uris of the others are returned to be loaded by the browser.
+func (s *Service) EndSession(ctx context.Context, sessionID string, req *domain.EndSessionRequest) (*domain.EndSession, error) {
+	logrus.Info("package service EndSession() session function called.")
+	clientID := req.ClientID
+	subjectID := ""
+	sid := ""
+	if session, err := s.GetUserSession(ctx, sessionID); err == nil {
+		sid = session.Sid
+	}
+	if req.IDTokenHint != "" {
+		claims, err := s.verifyIDTokenHint(ctx, req.IDTokenHint)
+		if err != nil {
+			return nil, errors.ErrInvalidRequest
+		}
+		aud, _ := claims["aud"].(string)
+		if clientID != "" && clientID != aud {
+			return nil, errors.ErrInvalidRequest
+		}
+		clientID = aud
+		subjectID, _ = claims["sub"].(string)
+		if sid == "" {
+			sid, _ = claims["sid"].(string)
+		}
+	}
+	result := &domain.EndSession{}
+	if req.PostLogoutRedirectURI != "" {
+		if clientID == "" {
+			return nil, errors.ErrInvalidRequest
+		}
+		client, err := s.repo.Client().GetDetailByClientID(ctx, clientID)
+		if err != nil || !client.HasPostLogoutRedirectUri(req.PostLogoutRedirectURI) {
+			return nil, errors.ErrInvalidRequest
+		}
+		uri, err := url.Parse(req.PostLogoutRedirectURI)
+		if err != nil {
+			return nil, errors.ErrInvalidRequest
+		}
+		if req.State != "" {
+			query := uri.Query()
+			query.Set("state", req.State)
+			uri.RawQuery = query.Encode()
+		}
+		result.RedirectURI = uri.String()
+	}
+	if sid == "" {
+		return result, nil
+	}
+	grants, err := s.repo.PersistedGrant().ListByFamily(ctx, sid)
+	if err != nil {
+		return nil, err
+	}
+	var session *domain.UserSession
+	for _, grant := range grants {
+		if grant.Type == domain.GrantTypeUserSession && !grant.Expiration.Before(time.Now()) {
+			session = &domain.UserSession{Sid: sid, SubjectID: grant.SubjectID, AuthTime: grant.CreationTime}
+		}
+	}
+	if session == nil {
+		// The session already ended
+		return result, nil
+	}
+	if subjectID != "" && subjectID != session.SubjectID {
+		return nil, errors.ErrInvalidRequest
+	}
+	if err := s.repo.PersistedGrant().DeleteByFamily(ctx, sid); err != nil {
+		return nil, err
+	}
+	var wg sync.WaitGroup
+	for _, grant := range grants {
+		if grant.Type != domain.GrantTypeSessionClient {
+			continue
+		}
+		client, err := s.repo.Client().GetDetailByClientID(ctx, grant.ClientID)
+		if err != nil {
+			continue
+		}
+		if uri := client.Property(domain.ClientPropertyBackChannelLogoutUri); uri != "" {
+			wg.Add(1)
+			go func(clientID, uri string) {
+				defer wg.Done()
+				if err := s.sendLogoutToken(ctx, clientID, uri, session); err != nil {
+					logrus.Warn("back-channel logout of client ", clientID, " failed: ", err)
+				}
+			}(client.ClientID, uri)
+			continue
+		}
+		if client.LogoutUri != "" {
+			result.FrontChannelLogoutUris = append(result.FrontChannelLogoutUris, s.frontChannelLogoutUri(client.LogoutUri, session.Sid))
+		}
+	}
+	wg.Wait()
+	return result, nil
+}
+
+// verifyIDTokenHint checks that an id_token was signed by one of the published keys of this
+// issuer. It may have expired, see OpenID Connect RP-Initiated Logout section 2.
+func (s *Service) verifyIDTokenHint(ctx context.Context, hint string) (jwt.MapClaims, error) {
+	jwks, err := s.GetJWKS(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims := jwt.MapClaims{}
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	_, err = parser.ParseWithClaims(hint, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		for _, jwk := range jwks.Keys {
+			if jwk.Kid == kid && jwk.Alg == t.Method.Alg() {
+				return util.ParseJSONWebKey(jwk)
+			}
+		}
+		return nil, errors.ErrInvalidRequest
+	})
+	if err != nil {
+		return nil, err
+	}
+	if iss, _ := claims["iss"].(string); iss != s.config.Issuer {
+		return nil, errors.ErrInvalidRequest
+	}
+	return claims, nil
+}
+
+// sendLogoutToken posts a signed logout token for the session to the back-channel logout uri of a
+// client, see OpenID Connect Back-Channel Logout section 2.5
+func (s *Service) sendLogoutToken(ctx context.Context, clientID, uri string, session *domain.UserSession) error {
+	key, err := s.GetSigningKey(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), jwt.MapClaims{
+		"iss":    s.config.Issuer,
+		"aud":    clientID,
+		"iat":    now.Unix(),
+		"exp":    now.Add(logoutTokenLifetime).Unix(),
+		"jti":    uuid.New().String(),
+		"sub":    session.SubjectID,
+		"sid":    session.Sid,
+		"events": map[string]interface{}{domain.BackChannelLogoutEvent: map[string]interface{}{}},
+	})
+	token.Header["kid"] = key.ID
+	token.Header["typ"] = "logout+jwt"
+	logoutToken, err := token.SignedString(key.PrivateKey)
+	if err != nil {
+		return err
+	}
+	resp, err := logoutClient.PostForm(uri, url.Values{"logout_token": {logoutToken}})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("logout uri answered with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// frontChannelLogoutUri adds the issuer and session id to the logout uri of a client,
+// see OpenID Connect Front-Channel Logout section 2
+func (s *Service) frontChannelLogoutUri(logoutUri, sid string) string {
+	separator := "?"
+	if strings.Contains(logoutUri, "?") {
+		separator = "&"
+	}
+	return logoutUri + separator + url.Values{"iss": {s.config.Issuer}, "sid": {sid}}.Encode()
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+func TestUserSessionSid(t *testing.T) {
+	svc := newTestService(newFakeRepository())
+	session, err := svc.CreateUserSession(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("CreateUserSession() error = %v", err)
+	}
+	if session.Sid == "" || session.Sid == session.SessionID {
+		t.Fatalf("CreateUserSession() sid = %q, want a value apart from the session cookie", session.Sid)
+	}
+	result, err := svc.GetUserSession(context.Background(), session.SessionID)
+	if err != nil {
+		t.Fatalf("GetUserSession() error = %v", err)
+	}
+	if result.Sid != session.Sid || result.SubjectID != "1" {
+		t.Errorf("GetUserSession() = %+v, want the session of the cookie", result)
+	}
+	if _, err := svc.GetUserSession(context.Background(), session.Sid); err != domain.ErrDataNotFound {
+		t.Errorf("GetUserSession() by sid error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
+
+func TestGetUserSessionIgnoresLegacySessions(t *testing.T) {
+	repo := newFakeRepository()
+	svc := newTestService(repo)
+	repo.grants.grants[domain.GrantKey("cookie", domain.GrantTypeUserSession)] = &domain.PersistedGrant{
+		Key:          domain.GrantKey("cookie", domain.GrantTypeUserSession),
+		Type:         domain.GrantTypeUserSession,
+		SubjectID:    "1",
+		FamilyID:     "cookie",
+		CreationTime: time.Now(),
+		Expiration:   time.Now().Add(time.Hour),
+	}
+	if _, err := svc.GetUserSession(context.Background(), "cookie"); err != domain.ErrDataNotFound {
+		t.Errorf("GetUserSession() error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
+
+func TestEndSessionFrontChannelSid(t *testing.T) {
+	repo := newFakeRepository()
+	repo.clients.clients["public"] = &domain.Client{ClientID: "public", Enabled: true, LogoutUri: "https://app.example.com/logout"}
+	svc := newTestService(repo)
+	ctx := context.Background()
+	session, err := svc.CreateUserSession(ctx, "1")
+	if err != nil {
+		t.Fatalf("CreateUserSession() error = %v", err)
+	}
+	if err := svc.AddSessionClient(ctx, session.SessionID, "public"); err != nil {
+		t.Fatalf("AddSessionClient() error = %v", err)
+	}
+
+	result, err := svc.EndSession(ctx, session.SessionID, &domain.EndSessionRequest{})
+	if err != nil {
+		t.Fatalf("EndSession() error = %v", err)
+	}
+	if len(result.FrontChannelLogoutUris) != 1 {
+		t.Fatalf("EndSession() front-channel logout uris = %v, want one", result.FrontChannelLogoutUris)
+	}
+	uri := result.FrontChannelLogoutUris[0]
+	if !strings.Contains(uri, "sid="+session.Sid) || strings.Contains(uri, session.SessionID) {
+		t.Errorf("EndSession() front-channel logout uri = %q, want the sid without the session cookie", uri)
+	}
+	if len(repo.grants.grants) != 0 {
+		t.Errorf("EndSession() left %d grants of the session", len(repo.grants.grants))
+	}
+}
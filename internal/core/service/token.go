@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sugaml/authserver/internal/adapter/storage/postgres/repository"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"gopkg.in/oauth2.v3"
+	"gopkg.in/oauth2.v3/errors"
+	"gopkg.in/oauth2.v3/models"
+)
+
+// grantCleanupInterval is how often expired persisted grants are deleted
+const grantCleanupInterval = 10 * time.Minute
+
+// TokenStore implements oauth2.TokenStore on the PersistedGrant table, one row per
+// code, access token and refresh token, so tokens survive restarts and are shared by replicas.
+// Rows issued with a refresh token share its family, which is revoked together on reuse.
+type TokenStore struct {
+	repo repository.IRepository
+}
+
+func newTokenStore(repo repository.IRepository) *TokenStore {
+	return &TokenStore{
+		repo: repo,
+	}
+}
+
+// Create stores a row for each handle of the token info
+func (s *TokenStore) Create(info oauth2.TokenInfo) error {
+	ctx := context.Background()
+	data := string(domain.ConvertToJson(info))
+	if code := info.GetCode(); code != "" {
+		return s.create(ctx, info, code, domain.GrantTypeAuthorizeToken, info.GetCodeCreateAt(), info.GetCodeExpiresIn(), data)
+	}
+	err := s.create(ctx, info, info.GetAccess(), domain.GrantTypeReferenceToken, info.GetAccessCreateAt(), info.GetAccessExpiresIn(), data)
+	if err != nil {
+		return err
+	}
+	if refresh := info.GetRefresh(); refresh != "" {
+		return s.create(ctx, info, refresh, domain.GrantTypeRefreshToken, info.GetRefreshCreateAt(), info.GetRefreshExpiresIn(), data)
+	}
+	return nil
+}
+
+// RemoveByCode deletes the authorization code, failing when another request already redeemed it
+func (s *TokenStore) RemoveByCode(code string) error {
+	err := s.repo.PersistedGrant().Redeem(context.Background(), domain.GrantKey(code, domain.GrantTypeAuthorizeToken))
+	if err == domain.ErrDataNotFound {
+		return errors.ErrInvalidGrant
+	}
+	return err
+}
+
+// RemoveByAccess deletes the access token
+func (s *TokenStore) RemoveByAccess(access string) error {
+	return s.repo.PersistedGrant().Delete(context.Background(), domain.GrantKey(access, domain.GrantTypeReferenceToken))
+}
+
+// RemoveByRefresh marks the refresh token consumed instead of deleting it, so a
+// later use of the rotated token is recognised as reuse. A token consumed by a
+// concurrent request was presented twice, which is reuse as well.
+func (s *TokenStore) RemoveByRefresh(refresh string) error {
+	ctx := context.Background()
+	key := domain.GrantKey(refresh, domain.GrantTypeRefreshToken)
+	err := s.repo.PersistedGrant().Consume(ctx, key, time.Now())
+	if err != domain.ErrDataNotFound {
+		return err
+	}
+	result, err := s.repo.PersistedGrant().Get(ctx, key)
+	if err == nil {
+		err = s.revokeFamily(ctx, result)
+	}
+	if err != nil && err != domain.ErrDataNotFound {
+		return err
+	}
+	return errors.ErrInvalidGrant
+}
+
+// GetByCode loads the token info of an authorization code
+func (s *TokenStore) GetByCode(code string) (oauth2.TokenInfo, error) {
+	return s.get(code, domain.GrantTypeAuthorizeToken)
+}
+
+// GetByAccess loads the token info of an access token
+func (s *TokenStore) GetByAccess(access string) (oauth2.TokenInfo, error) {
+	return s.get(access, domain.GrantTypeReferenceToken)
+}
+
+// GetByRefresh loads the token info of a refresh token. Presenting a consumed refresh
+// token means it was stolen or replayed, so every token of its family is revoked.
+func (s *TokenStore) GetByRefresh(refresh string) (oauth2.TokenInfo, error) {
+	ctx := context.Background()
+	result, err := s.repo.PersistedGrant().Get(ctx, domain.GrantKey(refresh, domain.GrantTypeRefreshToken))
+	if err == domain.ErrDataNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if result.ConsumedTime == nil {
+		info := domain.ConvertFromJson[models.Token]([]byte(result.Data))
+		return &info, nil
+	}
+	if err := s.revokeFamily(ctx, result); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// revokeFamily deletes every token issued in the family of a reused refresh token and audits it
+func (s *TokenStore) revokeFamily(ctx context.Context, result *domain.PersistedGrant) error {
+	logrus.Warn("refresh token reuse detected, revoking family ", result.FamilyID)
+	if err := s.repo.PersistedGrant().DeleteByFamily(ctx, result.FamilyID); err != nil {
+		return err
+	}
+	now := time.Now()
+	_, err := s.repo.AuditMessage().Create(ctx, &domain.AuditMessage{
+		ID:         uuid.New().String(),
+		CreatedUtc: now,
+		UpdatedUtc: now,
+		User:       result.SubjectID,
+		Message:    "refresh token reuse detected for client " + result.ClientID + ", token family " + result.FamilyID + " revoked",
+	})
+	return err
+}
+
+// DescribeRefreshToken returns an unconsumed and unexpired refresh token with the key it is bound to.
+// Unlike the token store it does not treat a rotated token as reuse, so introspecting or revoking one,
+// or checking its binding before the client is authenticated, is harmless.
+func (s *Service) DescribeRefreshToken(ctx context.Context, refresh string) (*domain.IntrospectionResponse, error) {
+	result, err := s.repo.PersistedGrant().Get(ctx, domain.GrantKey(refresh, domain.GrantTypeRefreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if result.ConsumedTime != nil || (!result.Expiration.IsZero() && result.Expiration.Before(time.Now())) {
+		return nil, domain.ErrDataNotFound
+	}
+	info := domain.ConvertFromJson[models.Token]([]byte(result.Data))
+	data := &domain.IntrospectionResponse{
+		Active:    true,
+		Scope:     info.GetScope(),
+		ClientID:  info.GetClientID(),
+		Subject:   info.GetUserID(),
+		IssuedAt:  info.GetRefreshCreateAt().Unix(),
+		TokenType: "refresh_token",
+	}
+	if !result.Expiration.IsZero() {
+		data.ExpiresAt = result.Expiration.Unix()
+	}
+	// The refresh token is bound to the key of the access token issued with it
+	claims := &JWTClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(info.GetAccess(), claims); err == nil {
+		data.Cnf = claims.Cnf
+	}
+	return data, nil
+}
+
+// create stores one handle, a zero lifetime never expires
+func (s *TokenStore) create(ctx context.Context, info oauth2.TokenInfo, handle, grantType string, createAt time.Time, expiresIn time.Duration, data string) error {
+	var expiration time.Time
+	if expiresIn > 0 {
+		expiration = createAt.Add(expiresIn)
+	}
+	var family string
+	if refresh := info.GetRefresh(); refresh != "" {
+		family = domain.RefreshTokenFamily(refresh)
+	}
+	_, err := s.repo.PersistedGrant().Create(ctx, &domain.PersistedGrant{
+		Key:          domain.GrantKey(handle, grantType),
+		Type:         grantType,
+		SubjectID:    info.GetUserID(),
+		ClientID:     info.GetClientID(),
+		FamilyID:     family,
+		CreationTime: createAt,
+		Expiration:   expiration,
+		Data:         data,
+	})
+	return err
+}
+
+// get returns nil without an error for unknown handles, as the manager expects
+func (s *TokenStore) get(handle, grantType string) (oauth2.TokenInfo, error) {
+	result, err := s.repo.PersistedGrant().Get(context.Background(), domain.GrantKey(handle, grantType))
+	if err == domain.ErrDataNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	info := domain.ConvertFromJson[models.Token]([]byte(result.Data))
+	return &info, nil
+}
+
+// RunGrantCleanup deletes expired codes, tokens and denylist entries until the context is cancelled
+func (s *Service) RunGrantCleanup(ctx context.Context) {
+	ticker := time.NewTicker(grantCleanupInterval)
+	defer ticker.Stop()
+	for {
+		count, err := s.repo.PersistedGrant().DeleteExpired(ctx, time.Now())
+		if err != nil {
+			logrus.Error("Error removing expired grants", "error", err)
+		} else if count > 0 {
+			logrus.Info("removed expired grants ", count)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
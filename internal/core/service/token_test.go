@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/sugaml/authserver/internal/core/domain"
+	"gopkg.in/oauth2.v3/errors"
+	"gopkg.in/oauth2.v3/models"
+)
+
+// issueTestToken stores an access and refresh token pair of the family "family"
+func issueTestToken(t *testing.T, store *TokenStore, access, secret string) string {
+	t.Helper()
+	refresh := domain.NewRefreshToken("family", secret)
+	now := time.Now()
+	err := store.Create(&models.Token{
+		ClientID:         "public",
+		UserID:           "1",
+		Scope:            "openid offline_access",
+		Access:           access,
+		AccessCreateAt:   now,
+		AccessExpiresIn:  time.Hour,
+		Refresh:          refresh,
+		RefreshCreateAt:  now,
+		RefreshExpiresIn: 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return refresh
+}
+
+// rotateTestToken consumes the first refresh token and issues its successor
+func rotateTestToken(t *testing.T, store *TokenStore) (string, string) {
+	t.Helper()
+	first := issueTestToken(t, store, "access-1", "one")
+	if err := store.RemoveByRefresh(first); err != nil {
+		t.Fatalf("RemoveByRefresh() error = %v", err)
+	}
+	return first, issueTestToken(t, store, "access-2", "two")
+}
+
+func assertFamilyRevoked(t *testing.T, repo *fakeRepository, store *TokenStore, second string) {
+	t.Helper()
+	if info, err := store.GetByRefresh(second); err != nil || info != nil {
+		t.Errorf("GetByRefresh() of the successor = %v, %v, want the family revoked", info, err)
+	}
+	if info, err := store.GetByAccess("access-2"); err != nil || info != nil {
+		t.Errorf("GetByAccess() of the successor = %v, %v, want the family revoked", info, err)
+	}
+	if len(repo.audits.messages) == 0 {
+		t.Error("no audit message recorded for the reuse")
+	}
+}
+
+func TestRemoveByRefreshReuse(t *testing.T) {
+	repo := newFakeRepository()
+	store := newTokenStore(repo)
+	first, second := rotateTestToken(t, store)
+
+	if err := store.RemoveByRefresh(first); err != errors.ErrInvalidGrant {
+		t.Fatalf("RemoveByRefresh() of a consumed token error = %v, want %v", err, errors.ErrInvalidGrant)
+	}
+	assertFamilyRevoked(t, repo, store, second)
+}
+
+func TestGetByRefreshReuse(t *testing.T) {
+	repo := newFakeRepository()
+	store := newTokenStore(repo)
+	first, second := rotateTestToken(t, store)
+
+	info, err := store.GetByRefresh(first)
+	if err != nil || info != nil {
+		t.Fatalf("GetByRefresh() of a consumed token = %v, %v, want nil", info, err)
+	}
+	assertFamilyRevoked(t, repo, store, second)
+}
+
+func TestDescribeRefreshToken(t *testing.T) {
+	repo := newFakeRepository()
+	store := newTokenStore(repo)
+	svc := newTestService(repo)
+	first, second := rotateTestToken(t, store)
+
+	data, err := svc.DescribeRefreshToken(context.Background(), second)
+	if err != nil {
+		t.Fatalf("DescribeRefreshToken() error = %v", err)
+	}
+	if !data.Active || data.ClientID != "public" || data.Subject != "1" {
+		t.Errorf("DescribeRefreshToken() = %+v, want the active token of the client", data)
+	}
+	if _, err := svc.DescribeRefreshToken(context.Background(), first); err != domain.ErrDataNotFound {
+		t.Errorf("DescribeRefreshToken() of a consumed token error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+	if info, err := store.GetByRefresh(second); err != nil || info == nil {
+		t.Errorf("GetByRefresh() of the successor = %v, %v, want the family kept", info, err)
+	}
+	if len(repo.audits.messages) != 0 {
+		t.Errorf("DescribeRefreshToken() recorded %d audit messages, want none", len(repo.audits.messages))
+	}
+}
+
+func TestDescribeRefreshTokenConfirmation(t *testing.T) {
+	repo := newFakeRepository()
+	store := newTokenStore(repo)
+	svc := newTestService(repo)
+	access, err := jwt.NewWithClaims(jwt.SigningMethodHS256, JWTClaims{
+		Cnf: &domain.Confirmation{JKT: "jkt"},
+	}).SignedString([]byte("key"))
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+	refresh := issueTestToken(t, store, access, "one")
+
+	data, err := svc.DescribeRefreshToken(context.Background(), refresh)
+	if err != nil {
+		t.Fatalf("DescribeRefreshToken() error = %v", err)
+	}
+	if data.Cnf == nil || data.Cnf.JKT != "jkt" {
+		t.Errorf("DescribeRefreshToken() cnf = %+v, want the binding of the access token", data.Cnf)
+	}
+}
@@ -3,11 +3,17 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/sugaml/authserver/internal/core/domain"
 	"github.com/sugaml/authserver/internal/core/util"
-	"golang.org/x/crypto/bcrypt"
+)
+
+// Lockout policy for users with lockout enabled
+const (
+	maxAccessFailedCount = 5
+	lockoutDuration      = 5 * time.Minute
 )
 
 // Register creates a new user
@@ -48,6 +54,15 @@ func (us *Service) GetUser(ctx context.Context, id uint64) (*domain.UserResponse
 	return domain.Convert[domain.User, domain.UserResponse](result), nil
 }
 
+// GetUserRoles gets the normalized names of the roles of a user
+func (us *Service) GetUserRoles(ctx context.Context, id uint64) ([]string, error) {
+	roles, err := us.repo.User().ListRoleNames(ctx, id)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+	return roles, nil
+}
+
 // List lists all users
 func (us *Service) ListUser(ctx context.Context, skip, limit uint64) ([]*domain.UserResponse, error) {
 	var userResponse []*domain.UserResponse
@@ -114,12 +129,39 @@ func (s *Service) LoginUser(ctx context.Context, req *domain.LoginRequest) (*dom
 	}
 	result, err := s.repo.User().GetByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, err
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrInvalidCredentials
+		}
+		return nil, domain.ErrInternal
+	}
+	if result.IsLockedOut() {
+		return nil, domain.ErrLockedOut
 	}
 	err = util.VerifyPassword(result.Password, req.Password)
-	if err != nil && err == bcrypt.ErrMismatchedHashAndPassword {
-		return nil, err
+	if err != nil {
+		if err := s.recordLoginFailure(ctx, result); err != nil {
+			return nil, domain.ErrInternal
+		}
+		return nil, domain.ErrInvalidCredentials
+	}
+	if result.AccessFailedCount > 0 || result.LockoutEnd != nil {
+		if err := s.repo.User().SetLockout(ctx, uint64(result.ID), 0, nil); err != nil {
+			return nil, domain.ErrInternal
+		}
 	}
 	logrus.Info("Loggedin user id :: ", result.ID)
 	return domain.Convert[domain.User, domain.UserResponse](result), nil
 }
+
+// recordLoginFailure counts a failed sign in and locks the user out once the limit is reached
+func (us *Service) recordLoginFailure(ctx context.Context, user *domain.User) error {
+	if !user.LockoutEnabled {
+		return nil
+	}
+	count, err := us.repo.User().IncrementAccessFailedCount(ctx, uint64(user.ID))
+	if err != nil || count < maxAccessFailedCount {
+		return err
+	}
+	end := time.Now().Add(lockoutDuration)
+	return us.repo.User().SetLockout(ctx, uint64(user.ID), 0, &end)
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"net/http"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+// ParseClientCredentials reads the client credentials from basic authorization, the form or
+// the TLS connection. The client id of an assertion defaults to its unverified subject, which
+// is checked later.
+func ParseClientCredentials(r *http.Request) *domain.ClientCredentials {
+	creds := &domain.ClientCredentials{
+		ClientID:      r.FormValue("client_id"),
+		Secret:        r.FormValue("client_secret"),
+		AssertionType: r.FormValue("client_assertion_type"),
+		Assertion:     r.FormValue("client_assertion"),
+	}
+	if username, password, ok := r.BasicAuth(); ok {
+		creds.ClientID, creds.Secret = username, password
+	}
+	if r.TLS != nil {
+		creds.Certificates = r.TLS.PeerCertificates
+	}
+	if creds.ClientID == "" && creds.Assertion != "" {
+		claims := jwt.MapClaims{}
+		if _, _, err := new(jwt.Parser).ParseUnverified(creds.Assertion, claims); err == nil {
+			creds.ClientID, _ = claims["sub"].(string)
+		}
+	}
+	return creds
+}
@@ -0,0 +1,71 @@
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func newFormRequest(form url.Values) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/connect/token", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func TestParseClientCredentialsForm(t *testing.T) {
+	creds := ParseClientCredentials(newFormRequest(url.Values{
+		"client_id":     {"client"},
+		"client_secret": {"secret"},
+	}))
+	if creds.ClientID != "client" || creds.Secret != "secret" || !creds.HasCredentials() {
+		t.Errorf("ParseClientCredentials() = %+v, want the form client id and secret", creds)
+	}
+}
+
+func TestParseClientCredentialsBasic(t *testing.T) {
+	r := newFormRequest(url.Values{"client_id": {"form"}, "client_secret": {"form-secret"}})
+	r.SetBasicAuth("basic", "basic-secret")
+	creds := ParseClientCredentials(r)
+	if creds.ClientID != "basic" || creds.Secret != "basic-secret" {
+		t.Errorf("ParseClientCredentials() = %+v, want basic authorization to win", creds)
+	}
+}
+
+func TestParseClientCredentialsPublic(t *testing.T) {
+	creds := ParseClientCredentials(newFormRequest(url.Values{"client_id": {"client"}}))
+	if creds.ClientID != "client" || creds.HasCredentials() {
+		t.Errorf("ParseClientCredentials() = %+v, want only a client id", creds)
+	}
+}
+
+func TestParseClientCredentialsAssertion(t *testing.T) {
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss": "client",
+		"sub": "client",
+	}).SignedString([]byte("key"))
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+	creds := ParseClientCredentials(newFormRequest(url.Values{
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {assertion},
+	}))
+	if creds.ClientID != "client" || creds.Assertion != assertion || !creds.HasCredentials() {
+		t.Errorf("ParseClientCredentials() = %+v, want the client id from the assertion subject", creds)
+	}
+}
+
+func TestParseClientCredentialsCertificate(t *testing.T) {
+	r := newFormRequest(url.Values{"client_id": {"client"}})
+	r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Raw: []byte("certificate")}}}
+	creds := ParseClientCredentials(r)
+	if len(creds.Certificates) != 1 || !creds.HasCredentials() {
+		t.Errorf("ParseClientCredentials() = %+v, want the TLS client certificate", creds)
+	}
+}
@@ -0,0 +1,125 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+// DPoPProofLifetime is how long after its iat a DPoP proof is accepted, proof ids are remembered as long
+const DPoPProofLifetime = 5 * time.Minute
+
+// dpopClockSkew tolerates proofs issued slightly in the future by a client clock
+const dpopClockSkew = time.Minute
+
+// DPoPAlgorithms are the asymmetric algorithms accepted for DPoP proofs
+var DPoPAlgorithms = []string{"RS256", "PS256", domain.AlgorithmES256, domain.AlgorithmEdDSA}
+
+// ErrInvalidDPoPProof is returned for a malformed, mis-signed or mismatching DPoP proof
+var ErrInvalidDPoPProof = errors.New("invalid DPoP proof")
+
+// VerifyDPoPProof checks the signature, key, method, uri and age of a DPoP proof, see RFC 9449
+// section 4.3. When the proof is sent with an access token, ath must be the hash of that token.
+func VerifyDPoPProof(proof, method, uri, accessToken string) (*domain.DPoPProof, error) {
+	var jwk domain.JSONWebKey
+	claims := jwt.MapClaims{}
+	parser := &jwt.Parser{ValidMethods: DPoPAlgorithms, SkipClaimsValidation: true}
+	_, err := parser.ParseWithClaims(proof, claims, func(t *jwt.Token) (interface{}, error) {
+		if typ, _ := t.Header["typ"].(string); typ != domain.DPoPProofType {
+			return nil, ErrInvalidDPoPProof
+		}
+		raw, err := json.Marshal(t.Header["jwk"])
+		if err != nil {
+			return nil, ErrInvalidDPoPProof
+		}
+		var members map[string]interface{}
+		if err := json.Unmarshal(raw, &members); err != nil || members["d"] != nil {
+			return nil, ErrInvalidDPoPProof
+		}
+		if err := json.Unmarshal(raw, &jwk); err != nil {
+			return nil, ErrInvalidDPoPProof
+		}
+		return ParseJSONWebKey(jwk)
+	})
+	if err != nil {
+		return nil, ErrInvalidDPoPProof
+	}
+	htm, _ := claims["htm"].(string)
+	htu, _ := claims["htu"].(string)
+	jti, _ := claims["jti"].(string)
+	iat, ok := claims["iat"].(float64)
+	if !ok || jti == "" || htm != method || !sameTargetURI(htu, uri) {
+		return nil, ErrInvalidDPoPProof
+	}
+	issuedAt := time.Unix(int64(iat), 0)
+	now := time.Now()
+	if issuedAt.After(now.Add(dpopClockSkew)) || issuedAt.Before(now.Add(-DPoPProofLifetime)) {
+		return nil, ErrInvalidDPoPProof
+	}
+	if accessToken != "" {
+		sum := sha256.Sum256([]byte(accessToken))
+		if ath, _ := claims["ath"].(string); ath != base64.RawURLEncoding.EncodeToString(sum[:]) {
+			return nil, ErrInvalidDPoPProof
+		}
+	}
+	jkt, err := JWKThumbprint(jwk)
+	if err != nil {
+		return nil, ErrInvalidDPoPProof
+	}
+	return &domain.DPoPProof{JKT: jkt, JTI: jti, IssuedAt: issuedAt}, nil
+}
+
+// JWKThumbprint returns the base64url SHA-256 thumbprint of the required members of a public JWK,
+// see RFC 7638 section 3
+func JWKThumbprint(jwk domain.JSONWebKey) (string, error) {
+	var members interface{}
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	default:
+		return "", ErrUnsupportedAlgorithm
+	}
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// sameTargetURI compares the htu claim with the request uri, ignoring query and fragment
+func sameTargetURI(htu, uri string) bool {
+	a, err := url.Parse(htu)
+	if err != nil {
+		return false
+	}
+	b, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Host, b.Host) && a.Path == b.Path
+}
@@ -0,0 +1,148 @@
+package util
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+const testDPoPURI = "https://auth.example.com/connect/token"
+
+// newDPoPProof signs the claims with key, after letting edit change the header
+func newDPoPProof(t *testing.T, key *ecdsa.PrivateKey, claims jwt.MapClaims, edit func(header map[string]interface{})) string {
+	t.Helper()
+	jwk, err := NewJSONWebKey("", domain.AlgorithmES256, &key.PublicKey)
+	if err != nil {
+		t.Fatalf("NewJSONWebKey() error = %v", err)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["typ"] = domain.DPoPProofType
+	token.Header["jwk"] = jwk
+	if edit != nil {
+		edit(token.Header)
+	}
+	proof, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+	return proof
+}
+
+func newDPoPClaims(edit func(claims jwt.MapClaims)) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"jti": "proof-1",
+		"htm": "POST",
+		"htu": testDPoPURI,
+		"iat": time.Now().Unix(),
+	}
+	if edit != nil {
+		edit(claims)
+	}
+	return claims
+}
+
+func TestVerifyDPoPProof(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	sum := sha256.Sum256([]byte("access-token"))
+	ath := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	tests := []struct {
+		name        string
+		claims      func(claims jwt.MapClaims)
+		header      func(header map[string]interface{})
+		uri         string
+		accessToken string
+		valid       bool
+	}{
+		{"valid proof", nil, nil, testDPoPURI, "", true},
+		{"query ignored", nil, nil, testDPoPURI + "?x=1", "", true},
+		{"wrong method", func(c jwt.MapClaims) { c["htm"] = "GET" }, nil, testDPoPURI, "", false},
+		{"wrong uri", func(c jwt.MapClaims) { c["htu"] = "https://auth.example.com/connect/userinfo" }, nil, testDPoPURI, "", false},
+		{"stale proof", func(c jwt.MapClaims) { c["iat"] = time.Now().Add(-2 * DPoPProofLifetime).Unix() }, nil, testDPoPURI, "", false},
+		{"future proof", func(c jwt.MapClaims) { c["iat"] = time.Now().Add(time.Hour).Unix() }, nil, testDPoPURI, "", false},
+		{"missing jti", func(c jwt.MapClaims) { delete(c, "jti") }, nil, testDPoPURI, "", false},
+		{"matching ath", func(c jwt.MapClaims) { c["ath"] = ath }, nil, testDPoPURI, "access-token", true},
+		{"mismatching ath", func(c jwt.MapClaims) { c["ath"] = ath }, nil, testDPoPURI, "other-token", false},
+		{"missing ath", nil, nil, testDPoPURI, "access-token", false},
+		{"wrong typ", nil, func(h map[string]interface{}) { h["typ"] = "JWT" }, testDPoPURI, "", false},
+		{"missing jwk", nil, func(h map[string]interface{}) { delete(h, "jwk") }, testDPoPURI, "", false},
+		{"private jwk", nil, func(h map[string]interface{}) {
+			jwk := h["jwk"].(domain.JSONWebKey)
+			h["jwk"] = map[string]string{"kty": jwk.Kty, "crv": jwk.Crv, "x": jwk.X, "y": jwk.Y, "d": "AA"}
+		}, testDPoPURI, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proof := newDPoPProof(t, key, newDPoPClaims(tt.claims), tt.header)
+			result, err := VerifyDPoPProof(proof, "POST", tt.uri, tt.accessToken)
+			if !tt.valid {
+				if err != ErrInvalidDPoPProof {
+					t.Errorf("VerifyDPoPProof() error = %v, want %v", err, ErrInvalidDPoPProof)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyDPoPProof() error = %v", err)
+			}
+			jwk, _ := NewJSONWebKey("", domain.AlgorithmES256, &key.PublicKey)
+			jkt, _ := JWKThumbprint(jwk)
+			if result.JKT != jkt || result.JTI != "proof-1" {
+				t.Errorf("VerifyDPoPProof() = %+v, want the thumbprint of the proof key", result)
+			}
+		})
+	}
+}
+
+func TestVerifyDPoPProofOtherKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	proof := newDPoPProof(t, other, newDPoPClaims(nil), func(h map[string]interface{}) {
+		h["jwk"], _ = NewJSONWebKey("", domain.AlgorithmES256, &key.PublicKey)
+	})
+	if _, err := VerifyDPoPProof(proof, "POST", testDPoPURI, ""); err != ErrInvalidDPoPProof {
+		t.Errorf("VerifyDPoPProof() error = %v, want %v", err, ErrInvalidDPoPProof)
+	}
+}
+
+func TestJWKThumbprint(t *testing.T) {
+	// Example of RFC 7638 section 3.1
+	jwk := domain.JSONWebKey{
+		Kty: "RSA",
+		N:   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		E:   "AQAB",
+	}
+	want := "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+	got, err := JWKThumbprint(jwk)
+	if err != nil {
+		t.Fatalf("JWKThumbprint() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("JWKThumbprint() = %q, want %q", got, want)
+	}
+	jwk.Kid, jwk.Alg, jwk.Use = "2011-04-29", "RS256", domain.KeyUseSignature
+	if got, _ := JWKThumbprint(jwk); got != want {
+		t.Errorf("JWKThumbprint() with optional members = %q, want %q", got, want)
+	}
+}
+
+func TestJWKThumbprintUnsupported(t *testing.T) {
+	if _, err := JWKThumbprint(domain.JSONWebKey{Kty: "oct"}); err != ErrUnsupportedAlgorithm {
+		t.Errorf("JWKThumbprint() error = %v, want %v", err, ErrUnsupportedAlgorithm)
+	}
+}
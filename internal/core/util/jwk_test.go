@@ -0,0 +1,74 @@
+package util
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/sugaml/authserver/internal/core/domain"
+)
+
+func TestJSONWebKeyRoundTrip(t *testing.T) {
+	for _, algorithm := range []string{domain.AlgorithmRS256, domain.AlgorithmES256, domain.AlgorithmEdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			signer, err := GenerateSigningKey(algorithm)
+			if err != nil {
+				t.Fatalf("GenerateSigningKey() error = %v", err)
+			}
+			jwk, err := NewJSONWebKey("kid-1", algorithm, signer.Public())
+			if err != nil {
+				t.Fatalf("NewJSONWebKey() error = %v", err)
+			}
+			if jwk.Kid != "kid-1" || jwk.Alg != algorithm || jwk.Use != domain.KeyUseSignature {
+				t.Errorf("NewJSONWebKey() = %+v, want kid, alg and use set", jwk)
+			}
+			publicKey, err := ParseJSONWebKey(jwk)
+			if err != nil {
+				t.Fatalf("ParseJSONWebKey() error = %v", err)
+			}
+			if !publicKey.(interface{ Equal(crypto.PublicKey) bool }).Equal(signer.Public()) {
+				t.Error("ParseJSONWebKey() returned another public key")
+			}
+		})
+	}
+}
+
+func TestPrivateKeyRoundTrip(t *testing.T) {
+	for _, algorithm := range []string{domain.AlgorithmRS256, domain.AlgorithmES256, domain.AlgorithmEdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			signer, err := GenerateSigningKey(algorithm)
+			if err != nil {
+				t.Fatalf("GenerateSigningKey() error = %v", err)
+			}
+			data, err := EncodePrivateKey(signer)
+			if err != nil {
+				t.Fatalf("EncodePrivateKey() error = %v", err)
+			}
+			decoded, err := DecodePrivateKey(data)
+			if err != nil {
+				t.Fatalf("DecodePrivateKey() error = %v", err)
+			}
+			if !decoded.Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(signer.Public()) {
+				t.Error("DecodePrivateKey() returned another key")
+			}
+		})
+	}
+}
+
+func TestGenerateSigningKeyUnsupported(t *testing.T) {
+	if _, err := GenerateSigningKey("HS256"); err != ErrUnsupportedAlgorithm {
+		t.Errorf("GenerateSigningKey() error = %v, want %v", err, ErrUnsupportedAlgorithm)
+	}
+}
+
+func TestParseJSONWebKeyRejectsOtherCurves(t *testing.T) {
+	tests := []domain.JSONWebKey{
+		{Kty: "EC", Crv: "P-384", X: "AA", Y: "AA"},
+		{Kty: "OKP", Crv: "X25519", X: "AA"},
+		{Kty: "oct"},
+	}
+	for _, jwk := range tests {
+		if _, err := ParseJSONWebKey(jwk); err == nil {
+			t.Errorf("ParseJSONWebKey(%+v) accepted the key", jwk)
+		}
+	}
+}
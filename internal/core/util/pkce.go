@@ -0,0 +1,17 @@
+package util
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+)
+
+// CodeChallengeS256 is the only PKCE transformation accepted by the server
+const CodeChallengeS256 = "S256"
+
+// VerifyCodeChallenge checks a PKCE code verifier against the S256 code challenge
+func VerifyCodeChallenge(verifier, challenge string) bool {
+	sum := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
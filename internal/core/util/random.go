@@ -0,0 +1,33 @@
+package util
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"math/big"
+)
+
+// userCodeCharset has no vowels or look-alike characters, see RFC 8628 section 6.1
+const userCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+
+// RandomToken returns n random bytes encoded as unpadded base64url
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// RandomUserCode returns n characters a user can read and type on another device
+func RandomUserCode(n int) (string, error) {
+	max := big.NewInt(int64(len(userCodeCharset)))
+	b := make([]byte, n)
+	for i := range b {
+		v, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = userCodeCharset[v.Int64()]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,48 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+)
+
+// secretHashPrefix marks a stored value as a salted SHA-256 secret hash
+const secretHashPrefix = "sha256$"
+
+// HashSecret returns a salted SHA-256 hash of a generated, high entropy secret
+func HashSecret(secret string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append(salt, secret...))
+	return secretHashPrefix + base64.RawStdEncoding.EncodeToString(salt) + "$" + base64.RawStdEncoding.EncodeToString(sum[:]), nil
+}
+
+// IsSecretHash reports whether a stored value is a secret hash rather than a plain text secret
+func IsSecretHash(stored string) bool {
+	return strings.HasPrefix(stored, secretHashPrefix)
+}
+
+// VerifySecret compares a secret with its stored hash in constant time
+func VerifySecret(stored, secret string) bool {
+	if !IsSecretHash(stored) {
+		return false
+	}
+	parts := strings.Split(strings.TrimPrefix(stored, secretHashPrefix), "$")
+	if len(parts) != 2 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[0])
+	if err != nil {
+		return false
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	sum := sha256.Sum256(append(salt, secret...))
+	return subtle.ConstantTimeCompare(sum[:], hash) == 1
+}
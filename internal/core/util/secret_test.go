@@ -0,0 +1,50 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashSecret(t *testing.T) {
+	hash, err := HashSecret("s3cret")
+	if err != nil {
+		t.Fatalf("HashSecret() error = %v", err)
+	}
+	if !IsSecretHash(hash) || strings.Contains(hash, "s3cret") {
+		t.Errorf("HashSecret() = %q, want a prefixed hash without the secret", hash)
+	}
+	other, err := HashSecret("s3cret")
+	if err != nil {
+		t.Fatalf("HashSecret() error = %v", err)
+	}
+	if hash == other {
+		t.Error("HashSecret() returned the same hash twice, want a random salt")
+	}
+}
+
+func TestVerifySecret(t *testing.T) {
+	hash, err := HashSecret("s3cret")
+	if err != nil {
+		t.Fatalf("HashSecret() error = %v", err)
+	}
+	tests := []struct {
+		name   string
+		stored string
+		secret string
+		want   bool
+	}{
+		{"matching secret", hash, "s3cret", true},
+		{"wrong secret", hash, "s3cret2", false},
+		{"empty secret", hash, "", false},
+		{"plain text value", "s3cret", "s3cret", false},
+		{"missing hash", secretHashPrefix + "c2FsdA", "s3cret", false},
+		{"invalid salt", secretHashPrefix + "!$AAAA", "s3cret", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifySecret(tt.stored, tt.secret); got != tt.want {
+				t.Errorf("VerifySecret() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}